@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CFConfig holds the parts of the configuration of the cf CLI (config.json)
+// that are needed to access LogCache.
+type CFConfig struct {
+	// Target is the URL of the Cloud Controller API.
+	Target string
+
+	AuthorizationEndpoint string
+	UaaEndpoint           string
+
+	// AccessToken includes the token type, e.g. "bearer eyJhbGciOi...".
+	AccessToken  string
+	RefreshToken string
+
+	UAAOAuthClient       string
+	UAAOAuthClientSecret string
+
+	SSLDisabled bool
+}
+
+// CFConfigPath returns the path of the configuration of the cf CLI. It is
+// $CF_HOME/.cf/config.json, or ~/.cf/config.json if CF_HOME is not set.
+func CFConfigPath() (string, error) {
+	home := os.Getenv("CF_HOME")
+	if home == "" {
+		var err error
+		home, err = os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return filepath.Join(home, ".cf", "config.json"), nil
+}
+
+// LoadCFConfig reads the configuration of the cf CLI from CFConfigPath.
+func LoadCFConfig() (*CFConfig, error) {
+	path, err := CFConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return ReadCFConfig(path)
+}
+
+// ReadCFConfig reads the configuration of the cf CLI from the given path.
+func ReadCFConfig(path string) (*CFConfig, error) {
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	var c CFConfig
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cf CLI config %s: %w", path, err)
+	}
+
+	if c.Target == "" {
+		return nil, fmt.Errorf("cf CLI config %s has no target (run 'cf login')", path)
+	}
+
+	return &c, nil
+}
+
+// LogCacheURL returns the URL of LogCache as derived from the target by
+// replacing its leading "api" label with "log-cache", e.g.
+// https://api.example.com becomes https://log-cache.example.com. It returns
+// an error for targets that do not start with an "api" label. Prefer the
+// log_cache link of the Cloud Controller API (see DiscoverCFEndpoints), as
+// NewClientFromCFConfig does.
+func (c *CFConfig) LogCacheURL() (string, error) {
+	u, err := url.Parse(c.Target)
+	if err != nil {
+		return "", err
+	}
+
+	host, ok := strings.CutPrefix(u.Host, "api.")
+	if !ok {
+		return "", fmt.Errorf("failed to derive LogCache URL from target %s", c.Target)
+	}
+
+	u.Host = "log-cache." + host
+	return u.String(), nil
+}
+
+// HTTPClient returns an Oauth2HTTPClient that uses the access token of the
+// configuration and replaces it via the refresh token once it expires.
+func (c *CFConfig) HTTPClient(opts ...Oauth2Option) *Oauth2HTTPClient {
+	uaaAddr := c.UaaEndpoint
+	if uaaAddr == "" {
+		uaaAddr = c.AuthorizationEndpoint
+	}
+
+	client := c.UAAOAuthClient
+	if client == "" {
+		client = "cf"
+	}
+
+	token := Oauth2Token{
+		RefreshToken: c.RefreshToken,
+	}
+
+	// Without an access token, the refresh token is used right away.
+	if tokenType, accessToken, ok := strings.Cut(c.AccessToken, " "); ok {
+		token.TokenType = tokenType
+		token.AccessToken = accessToken
+		token.Expiry = jwtExpiry(accessToken)
+	}
+
+	var defaultOpts []Oauth2Option
+	if c.SSLDisabled {
+		defaultOpts = append(defaultOpts, WithOauth2HTTPClient(c.insecureHTTPClient()))
+	}
+
+	switch {
+	case token.AccessToken != "":
+		defaultOpts = append(defaultOpts, WithOauth2Token(token))
+	case c.RefreshToken != "":
+		defaultOpts = append(defaultOpts, WithOauth2RefreshToken(c.RefreshToken))
+	}
+
+	return NewOauth2HTTPClient(uaaAddr, client, c.UAAOAuthClientSecret, append(defaultOpts, opts...)...)
+}
+
+func (c *CFConfig) insecureHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true, //nolint:gosec
+			},
+		},
+	}
+}
+
+// NewClientFromCFConfig creates a Client from the configuration of the cf
+// CLI (see LoadCFConfig), authenticated as the user that is logged in. The
+// address of LogCache is discovered via the Cloud Controller API the cf CLI
+// targets. The given options are applied after the ones derived from the
+// configuration.
+func NewClientFromCFConfig(ctx context.Context, opts ...ClientOption) (*Client, error) {
+	c, err := LoadCFConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.AccessToken == "" && c.RefreshToken == "" {
+		return nil, errors.New("cf CLI config has no tokens (run 'cf login')")
+	}
+
+	var h HTTPClient
+	if c.SSLDisabled {
+		h = c.insecureHTTPClient()
+	}
+
+	e, err := DiscoverCFEndpoints(ctx, c.Target, h)
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append([]ClientOption{WithHTTPClient(c.HTTPClient())}, opts...)
+	return e.Client(opts...), nil
+}
+
+// jwtExpiry returns the time the given JWT expires at. It returns the zero
+// time if the token is not a JWT or does not expire.
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(claims.Exp, 0)
+}
@@ -0,0 +1,235 @@
+package client_test
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	client "code.cloudfoundry.org/go-log-cache/v3"
+)
+
+func TestReadCFConfig(t *testing.T) {
+	t.Parallel()
+
+	path := writeCFConfig(t, t.TempDir(), `{
+		"Target": "https://api.sys.example.com",
+		"UaaEndpoint": "https://uaa.sys.example.com",
+		"AccessToken": "bearer some-token",
+		"RefreshToken": "some-refresh-token",
+		"UAAOAuthClient": "cf"
+	}`)
+
+	c, err := client.ReadCFConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.UaaEndpoint != "https://uaa.sys.example.com" || c.RefreshToken != "some-refresh-token" {
+		t.Fatalf("wrong config: %+v", c)
+	}
+
+	addr, err := c.LogCacheURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if addr != "https://log-cache.sys.example.com" {
+		t.Fatalf("wrong LogCache URL: %s", addr)
+	}
+}
+
+func TestCFConfigLogCacheURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		target   string
+		expected string
+	}{
+		{"https://api.example.com", "https://log-cache.example.com"},
+		{"https://api.sys.example.com:8443/", "https://log-cache.sys.example.com:8443/"},
+		{"https://rapid.example.com", ""},
+		{"https://capi.example.com", ""},
+		{"https://cf.api.example.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			t.Parallel()
+
+			c := client.CFConfig{Target: tt.target}
+			addr, err := c.LogCacheURL()
+			if tt.expected == "" {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", addr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if addr != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, addr)
+			}
+		})
+	}
+}
+
+func TestReadCFConfigRequiresTarget(t *testing.T) {
+	t.Parallel()
+
+	path := writeCFConfig(t, t.TempDir(), `{}`)
+
+	if _, err := client.ReadCFConfig(path); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestCFConfigHTTPClientUsesStoredToken(t *testing.T) {
+	t.Parallel()
+
+	token := newJWT(time.Now().Add(time.Hour))
+	c := &client.CFConfig{
+		Target:       "https://api.sys.example.com",
+		UaaEndpoint:  "https://uaa.sys.example.com",
+		AccessToken:  "bearer " + token,
+		RefreshToken: "some-refresh-token",
+	}
+
+	stubClient := newStubHTTPClient()
+	req, err := http.NewRequest("GET", "http://some-target.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.HTTPClient(client.WithOauth2HTTPClient(stubClient)).Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stubClient.reqs) != 1 {
+		t.Fatalf("expected to not get a token: %d", len(stubClient.reqs))
+	}
+
+	if stubClient.reqs[0].Header.Get("Authorization") != "bearer "+token {
+		t.Fatalf("expected the stored token to be used: %s", stubClient.reqs[0].Header.Get("Authorization"))
+	}
+}
+
+func TestCFConfigHTTPClientRefreshesExpiredToken(t *testing.T) {
+	t.Parallel()
+
+	c := &client.CFConfig{
+		Target:       "https://api.sys.example.com",
+		UaaEndpoint:  "https://uaa.sys.example.com",
+		AccessToken:  "bearer " + newJWT(time.Now().Add(-time.Hour)),
+		RefreshToken: "some-refresh-token",
+	}
+
+	stubClient := newStubHTTPClient()
+	req, err := http.NewRequest("GET", "http://some-target.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.HTTPClient(client.WithOauth2HTTPClient(stubClient)).Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stubClient.reqs) != 2 {
+		t.Fatalf("expected to get a new token: %d", len(stubClient.reqs))
+	}
+
+	if stubClient.reqs[0].URL.Host != "uaa.sys.example.com" {
+		t.Fatalf("expected Host to equal uaa.sys.example.com: %s", stubClient.reqs[0].URL.Host)
+	}
+
+	query, err := url.ParseQuery(string(stubClient.bodies[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if query.Get("grant_type") != "refresh_token" || query.Get("refresh_token") != "some-refresh-token" {
+		t.Fatalf("expected the refresh token to be used: %v", query)
+	}
+
+	if query.Get("client_id") != "cf" {
+		t.Fatalf("expected client_id to equal cf: %s", query.Get("client_id"))
+	}
+}
+
+func TestNewClientFromCFConfigRespectsCFHome(t *testing.T) {
+	logCache := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/info":
+			w.Write([]byte(`{"version":"2.0.0"}`)) //nolint:errcheck
+		case "/api/v1/meta":
+			w.Write([]byte(`{"meta":{"source-0":{"count":1}}}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer logCache.Close()
+
+	// The host of the Cloud Controller does not start with "api", so the
+	// address of LogCache has to be discovered.
+	cc := newStubCloudController(t, fmt.Sprintf(`{"links":{"log_cache":{"href":%q}}}`, logCache.URL))
+
+	home := t.TempDir()
+	if err := os.Mkdir(filepath.Join(home, ".cf"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeCFConfig(t, filepath.Join(home, ".cf"), fmt.Sprintf(`{
+		"Target": %q,
+		"AccessToken": "bearer %s"
+	}`, cc.URL, newJWT(time.Now().Add(time.Hour))))
+	t.Setenv("CF_HOME", home)
+
+	path, err := client.CFConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if path != filepath.Join(home, ".cf", "config.json") {
+		t.Fatalf("wrong path: %s", path)
+	}
+
+	c, err := client.NewClientFromCFConfig(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := c.Meta(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if meta["source-0"].GetCount() != 1 {
+		t.Fatalf("wrong meta: %v", meta)
+	}
+}
+
+func writeCFConfig(t *testing.T, dir, config string) string {
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func newJWT(exp time.Time) string {
+	enc := base64.RawURLEncoding
+	return fmt.Sprintf("%s.%s.%s",
+		enc.EncodeToString([]byte(`{"alg":"RS256"}`)),
+		enc.EncodeToString(fmt.Appendf(nil, `{"exp":%d}`, exp.Unix())),
+		enc.EncodeToString([]byte("some-signature")),
+	)
+}
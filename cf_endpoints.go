@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CFEndpoints are the endpoints of a Cloud Foundry deployment as advertised
+// by the root document of the Cloud Controller API.
+type CFEndpoints struct {
+	LogCache string
+	UAA      string
+	Login    string
+}
+
+// DiscoverCFEndpoints fetches the root document of the Cloud Controller API
+// at the given address and returns the endpoints it links to. A nil
+// HTTPClient defaults to the same default as Client.
+func DiscoverCFEndpoints(ctx context.Context, apiAddr string, h HTTPClient) (*CFEndpoints, error) {
+	if h == nil {
+		h = &http.Client{
+			Timeout: 5 * time.Second,
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiAddr, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.Path = "/"
+
+	resp, err := h.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPStatusError(req, resp)
+	}
+
+	type link struct {
+		Href string `json:"href"`
+	}
+
+	var root struct {
+		Links struct {
+			LogCache link `json:"log_cache"`
+			UAA      link `json:"uaa"`
+			Login    link `json:"login"`
+		} `json:"links"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&root); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal root document of %s: %s", apiAddr, err)
+	}
+
+	if root.Links.LogCache.Href == "" {
+		return nil, fmt.Errorf("root document of %s has no log_cache link", apiAddr)
+	}
+
+	return &CFEndpoints{
+		LogCache: root.Links.LogCache.Href,
+		UAA:      root.Links.UAA.Href,
+		Login:    root.Links.Login.Href,
+	}, nil
+}
+
+// Oauth2HTTPClient creates an Oauth2HTTPClient that gets its tokens from
+// UAA.
+func (e *CFEndpoints) Oauth2HTTPClient(client, clientSecret string, opts ...Oauth2Option) *Oauth2HTTPClient {
+	return NewOauth2HTTPClient(e.UAA, client, clientSecret, opts...)
+}
+
+// Client creates a Client for LogCache.
+func (e *CFEndpoints) Client(opts ...ClientOption) *Client {
+	return NewClient(e.LogCache, opts...)
+}
+
+// NewClientFromCFAPI discovers the endpoints of the Cloud Controller API at
+// the given address and creates a Client that is authenticated via UAA with
+// the given client credentials. Use WithOauth2HTTPUser to authenticate as a
+// user instead.
+func NewClientFromCFAPI(ctx context.Context, apiAddr, client, clientSecret string, opts ...Oauth2Option) (*Client, error) {
+	e, err := DiscoverCFEndpoints(ctx, apiAddr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.UAA == "" {
+		return nil, fmt.Errorf("root document of %s has no uaa link", apiAddr)
+	}
+
+	return e.Client(WithHTTPClient(e.Oauth2HTTPClient(client, clientSecret, opts...))), nil
+}
@@ -0,0 +1,123 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	client "code.cloudfoundry.org/go-log-cache/v3"
+)
+
+func TestNewClientFromCFAPI(t *testing.T) {
+	t.Parallel()
+
+	uaa := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte(`{"token_type":"bearer","access_token":"some-token"}`)) //nolint:errcheck
+	}))
+	defer uaa.Close()
+
+	logCache := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "bearer some-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v1/info":
+			w.Write([]byte(`{"version":"2.0.0"}`)) //nolint:errcheck
+		case "/api/v1/meta":
+			w.Write([]byte(`{"meta":{"source-0":{"count":1}}}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer logCache.Close()
+
+	cc := newStubCloudController(t, fmt.Sprintf(
+		`{"links":{"log_cache":{"href":%q},"uaa":{"href":%q}}}`,
+		logCache.URL,
+		uaa.URL,
+	))
+
+	c, err := client.NewClientFromCFAPI(context.Background(), cc.URL, "some-client", "some-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := c.Meta(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if meta["source-0"].GetCount() != 1 {
+		t.Fatalf("wrong meta: %v", meta)
+	}
+}
+
+func TestDiscoverCFEndpoints(t *testing.T) {
+	t.Parallel()
+
+	cc := newStubCloudController(t, `{"links":{
+		"log_cache":{"href":"https://log-cache.example.com"},
+		"uaa":{"href":"https://uaa.example.com"},
+		"login":{"href":"https://login.example.com"}
+	}}`)
+
+	e, err := client.DiscoverCFEndpoints(context.Background(), cc.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e.LogCache != "https://log-cache.example.com" || e.UAA != "https://uaa.example.com" || e.Login != "https://login.example.com" {
+		t.Fatalf("wrong endpoints: %+v", e)
+	}
+}
+
+func TestDiscoverCFEndpointsRequiresLogCacheLink(t *testing.T) {
+	t.Parallel()
+
+	cc := newStubCloudController(t, `{"links":{"uaa":{"href":"https://uaa.example.com"}}}`)
+
+	if _, err := client.DiscoverCFEndpoints(context.Background(), cc.URL, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestDiscoverCFEndpointsReturnsStatusError(t *testing.T) {
+	t.Parallel()
+
+	cc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer cc.Close()
+
+	_, err := client.DiscoverCFEndpoints(context.Background(), cc.URL, nil)
+
+	var statusErr *client.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected a StatusError with status code 502: %v", err)
+	}
+}
+
+// newStubCloudController serves the given root document.
+func newStubCloudController(t *testing.T, root string) *httptest.Server {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Write([]byte(root)) //nolint:errcheck
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
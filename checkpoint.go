@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Checkpointer persists how far Walk has progressed for each source ID, so
+// that a Walk can resume where a previous one stopped (see
+// WithWalkCheckpointer).
+type Checkpointer interface {
+	// Load returns the timestamp of the last envelope that was delivered
+	// for the source ID. It returns false if there is none.
+	Load(ctx context.Context, sourceID string) (time.Time, bool, error)
+
+	// Save records the timestamp of the last envelope that was delivered
+	// for the source ID.
+	Save(ctx context.Context, sourceID string, t time.Time) error
+}
+
+// WithWalkCheckpointer sets the Checkpointer for the Walk. If the
+// Checkpointer has a checkpoint for the source ID, Walk starts at it instead
+// of the start time. After every batch the Visitor accepted (returned true
+// for), Walk saves the timestamp of the last envelope of the batch.
+//
+// Walk resumes at the saved timestamp itself (and not after it) as the
+// envelopes with that timestamp might not all have been delivered. This
+// means envelopes are delivered at least once: a resumed Walk redelivers
+// the envelopes with the saved timestamp.
+func WithWalkCheckpointer(cp Checkpointer) WalkOption {
+	return func(c *WalkConfig) {
+		c.Checkpointer = cp
+	}
+}
+
+// loadCheckpoint sets the start time of the walk to the checkpoint of the
+// source ID, if there is one. It returns false if the checkpoint fails to
+// load, as starting anywhere else could skip envelopes.
+func (c *WalkConfig) loadCheckpoint(ctx context.Context, sourceID string) bool {
+	if c.Checkpointer == nil {
+		return true
+	}
+
+	t, ok, err := c.Checkpointer.Load(ctx, sourceID)
+	if err != nil {
+		c.Log.Printf("failed to load checkpoint: %s", err)
+		return false
+	}
+
+	if ok {
+		c.Start = t.UnixNano()
+	}
+
+	return true
+}
+
+// saveCheckpoint saves the timestamp of the last envelope of a visited
+// batch. Failures are only logged.
+func (c *WalkConfig) saveCheckpoint(ctx context.Context, sourceID string, last int64) {
+	if c.Checkpointer == nil {
+		return
+	}
+
+	if err := c.Checkpointer.Save(ctx, sourceID, time.Unix(0, last)); err != nil {
+		c.Log.Printf("failed to save checkpoint: %s", err)
+	}
+}
+
+// FileCheckpointer is a Checkpointer that stores the checkpoints of all
+// source IDs as JSON in a single file. The file is replaced atomically on
+// every Save, so it is never left partially written.
+type FileCheckpointer struct {
+	path string
+
+	mu          sync.Mutex
+	checkpoints map[string]int64
+}
+
+// NewFileCheckpointer returns a new FileCheckpointer that stores its
+// checkpoints at the given path. The file is created on the first Save.
+func NewFileCheckpointer(path string) *FileCheckpointer {
+	return &FileCheckpointer{
+		path: path,
+	}
+}
+
+// Load implements Checkpointer.
+func (c *FileCheckpointer) Load(_ context.Context, sourceID string) (time.Time, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.read(); err != nil {
+		return time.Time{}, false, err
+	}
+
+	ts, ok := c.checkpoints[sourceID]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	return time.Unix(0, ts), true, nil
+}
+
+// Save implements Checkpointer.
+func (c *FileCheckpointer) Save(_ context.Context, sourceID string, t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.read(); err != nil {
+		return err
+	}
+
+	c.checkpoints[sourceID] = t.UnixNano()
+
+	return c.write()
+}
+
+// read loads the checkpoints from the file once.
+func (c *FileCheckpointer) read() error {
+	if c.checkpoints != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.path) //nolint:gosec
+	if errors.Is(err, fs.ErrNotExist) {
+		c.checkpoints = make(map[string]int64)
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	checkpoints := make(map[string]int64)
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return fmt.Errorf("failed to parse checkpoints in %s: %w", c.path, err)
+	}
+	c.checkpoints = checkpoints
+
+	return nil
+}
+
+// write replaces the file via a temporary file in the same directory.
+func (c *FileCheckpointer) write() error {
+	data, err := json.Marshal(c.checkpoints)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck
+
+	if _, err := f.Write(data); err != nil {
+		f.Close() //nolint:errcheck
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close() //nolint:errcheck
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), c.path)
+}
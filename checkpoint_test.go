@@ -0,0 +1,103 @@
+package client_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	client "code.cloudfoundry.org/go-log-cache/v3"
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+)
+
+func TestFileCheckpointerSavesAndLoads(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	ctx := context.Background()
+
+	c := client.NewFileCheckpointer(path)
+	if _, ok, err := c.Load(ctx, "some-id"); err != nil || ok {
+		t.Fatalf("expected no checkpoint: %v, %v", ok, err)
+	}
+
+	if err := c.Save(ctx, "some-id", time.Unix(0, 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Save(ctx, "other-id", time.Unix(0, 2)); err != nil {
+		t.Fatal(err)
+	}
+
+	c = client.NewFileCheckpointer(path)
+	ts, ok, err := c.Load(ctx, "some-id")
+	if err != nil || !ok || ts.UnixNano() != 1 {
+		t.Fatalf("wrong checkpoint: %v, %v, %v", ts.UnixNano(), ok, err)
+	}
+
+	ts, ok, err = c.Load(ctx, "other-id")
+	if err != nil || !ok || ts.UnixNano() != 2 {
+		t.Fatalf("wrong checkpoint: %v, %v, %v", ts.UnixNano(), ok, err)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("expected temporary files to be removed: %v", entries)
+	}
+}
+
+func TestWalkResumesFromCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cp := client.NewFileCheckpointer(filepath.Join(t.TempDir(), "checkpoints.json"))
+	if err := cp.Save(ctx, "some-id", time.Unix(0, 5)); err != nil {
+		t.Fatal(err)
+	}
+
+	r := newStubReader()
+	r.envelopes = [][]*loggregator_v2.Envelope{
+		{{Timestamp: 5}, {Timestamp: 6}},
+		{{Timestamp: 7}},
+	}
+	r.errs = []error{nil, nil}
+
+	var batches int
+	client.Walk(ctx, "some-id", func(es []*loggregator_v2.Envelope) bool {
+		batches++
+		return batches < 2
+	}, r.read, client.WithWalkCheckpointer(cp))
+
+	if r.starts[0] != 5 {
+		t.Fatalf("expected to start at the checkpoint: %d", r.starts[0])
+	}
+
+	// The second batch was not accepted by the visitor.
+	ts, _, err := cp.Load(ctx, "some-id")
+	if err != nil || ts.UnixNano() != 6 {
+		t.Fatalf("expected checkpoint to be 6: %d, %v", ts.UnixNano(), err)
+	}
+}
+
+func TestWalkDoesNotReadIfCheckpointFailsToLoad(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	if err := os.WriteFile(path, []byte("invalid"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := newStubReader()
+	client.Walk(context.Background(), "some-id", func([]*loggregator_v2.Envelope) bool {
+		return true
+	}, r.read, client.WithWalkCheckpointer(client.NewFileCheckpointer(path)))
+
+	if len(r.starts) != 0 {
+		t.Fatalf("expected no reads: %d", len(r.starts))
+	}
+}
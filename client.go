@@ -6,9 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 
 	marshaler "code.cloudfoundry.org/go-log-cache/v3/internal"
@@ -23,15 +25,21 @@ import (
 
 // Client reads from LogCache via the RESTful or gRPC API.
 type Client struct {
-	addr        string
-	baseApiPath string
+	addr string
+
+	// baseApiPath is determined via the LogCache version by the first
+	// request that needs it. Requests run concurrently, e.g. via WalkMany.
+	baseApiPathMu sync.Mutex
+	baseApiPath   string
 
 	httpClient       HTTPClient
 	grpcClient       logcache_v1.EgressClient
 	promqlGrpcClient logcache_v1.PromQLQuerierClient
+
+	retryPolicy *RetryPolicy
 }
 
-// NewIngressClient creates a Client.
+// NewClient creates a Client.
 func NewClient(addr string, opts ...ClientOption) *Client {
 	c := &Client{
 		addr: addr,
@@ -97,15 +105,27 @@ func WithViaGRPC(opts ...grpc.DialOption) ClientOption {
 }
 
 // Read queries the LogCache and returns the given envelopes. To override any
-// query defaults (e.g., end time), use the according option.
+// query defaults (e.g., end time), use the according option. It returns an
+// error without querying the LogCache if the options are invalid.
 func (c *Client) Read(
 	ctx context.Context,
 	sourceID string,
 	start time.Time,
 	opts ...ReadOption,
 ) ([]*loggregator_v2.Envelope, error) {
+	req, err := newReadRequest(sourceID, start, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return withRetry(ctx, c.retryPolicy, func() ([]*loggregator_v2.Envelope, error) {
+		return c.read(ctx, req)
+	})
+}
+
+func (c *Client) read(ctx context.Context, r *logcache_v1.ReadRequest) ([]*loggregator_v2.Envelope, error) {
 	if c.grpcClient != nil {
-		return c.grpcRead(ctx, sourceID, start, opts)
+		return c.grpcRead(ctx, r)
 	}
 
 	u, err := url.Parse(c.addr)
@@ -118,14 +138,9 @@ func (c *Client) Read(
 		return nil, err
 	}
 
-	u.Path = fmt.Sprintf("%s/read/%s", baseApiPath, sourceID)
+	u.Path = fmt.Sprintf("%s/read/%s", baseApiPath, r.GetSourceId())
 	q := u.Query()
-	q.Set("start_time", strconv.FormatInt(start.UnixNano(), 10))
-
-	// allow the given options to configure the URL.
-	for _, o := range opts {
-		o(u, q)
-	}
+	setReadQuery(q, r)
 	u.RawQuery = q.Encode()
 
 	req, err := http.NewRequest("GET", u.String(), nil)
@@ -141,7 +156,7 @@ func (c *Client) Read(
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return nil, newHTTPStatusError(req, resp)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -149,103 +164,129 @@ func (c *Client) Read(
 		return nil, err
 	}
 
-	var r logcache_v1.ReadResponse
-	if err := protojson.Unmarshal(body, &r); err != nil {
+	var rr logcache_v1.ReadResponse
+	if err := protojson.Unmarshal(body, &rr); err != nil {
 		return nil, err
 	}
 
-	return r.GetEnvelopes().GetBatch(), nil
+	return rr.GetEnvelopes().GetBatch(), nil
 }
 
-// ReadOption configures the URL that is used to submit the query. The
-// RawQuery is set to the decoded query parameters after each option is
-// invoked.
-type ReadOption func(u *url.URL, q url.Values)
+// ReadOption configures the request that is sent to the LogCache. Both the
+// HTTP and the gRPC transport are derived from it.
+type ReadOption func(r *logcache_v1.ReadRequest)
 
 // WithEndTime sets the 'end_time' query parameter to the given time. It
 // defaults to empty, and therefore the end of the cache.
 func WithEndTime(t time.Time) ReadOption {
-	return func(u *url.URL, q url.Values) {
-		q.Set("end_time", strconv.FormatInt(t.UnixNano(), 10))
+	return func(r *logcache_v1.ReadRequest) {
+		r.EndTime = t.UnixNano()
 	}
 }
 
 // WithLimit sets the 'limit' query parameter to the given value. It
 // defaults to empty, and therefore 100 envelopes.
 func WithLimit(limit int) ReadOption {
-	return func(u *url.URL, q url.Values) {
-		q.Set("limit", strconv.Itoa(limit))
+	return func(r *logcache_v1.ReadRequest) {
+		r.Limit = int64(limit)
 	}
 }
 
 // WithEnvelopeTypes sets the 'envelope_types' query parameter to the given
 // value. It defaults to empty, and therefore any envelope type.
 func WithEnvelopeTypes(t ...logcache_v1.EnvelopeType) ReadOption {
-	return func(u *url.URL, q url.Values) {
-		for _, v := range t {
-			q.Add("envelope_types", v.String())
-		}
+	return func(r *logcache_v1.ReadRequest) {
+		r.EnvelopeTypes = append(r.EnvelopeTypes, t...)
 	}
 }
 
 // WithDescending set the 'descending' query parameter to true. It defaults to
 // false, yielding ascending order.
 func WithDescending() ReadOption {
-	return func(u *url.URL, q url.Values) {
-		q.Set("descending", "true")
+	return func(r *logcache_v1.ReadRequest) {
+		r.Descending = true
 	}
 }
 
+// WithNameFilter sets the 'name_filter' query parameter to the given
+// regular expression. It defaults to empty, and therefore any name.
 func WithNameFilter(nameFilter string) ReadOption {
-	return func(u *url.URL, q url.Values) {
-		q.Set("name_filter", nameFilter)
+	return func(r *logcache_v1.ReadRequest) {
+		r.NameFilter = nameFilter
 	}
 }
 
-func (c *Client) grpcRead(ctx context.Context, sourceID string, start time.Time, opts []ReadOption) ([]*loggregator_v2.Envelope, error) {
-	u := &url.URL{}
-	q := u.Query()
-	// allow the given options to configure the URL.
+// newReadRequest applies the options and validates the resulting request.
+func newReadRequest(sourceID string, start time.Time, opts []ReadOption) (*logcache_v1.ReadRequest, error) {
+	r := &logcache_v1.ReadRequest{
+		SourceId:  sourceID,
+		StartTime: start.UnixNano(),
+	}
+
 	for _, o := range opts {
-		o(u, q)
+		o(r)
 	}
 
-	req := &logcache_v1.ReadRequest{
-		SourceId:  sourceID,
-		StartTime: start.UnixNano(),
+	if r.GetLimit() < 0 {
+		return nil, fmt.Errorf("invalid limit %d: must not be negative", r.GetLimit())
+	}
+
+	if r.GetEndTime() != 0 && r.GetEndTime() < r.GetStartTime() {
+		return nil, fmt.Errorf("invalid end time %d: must not be before start time %d", r.GetEndTime(), r.GetStartTime())
+	}
+
+	for _, t := range r.GetEnvelopeTypes() {
+		if _, ok := logcache_v1.EnvelopeType_name[int32(t)]; !ok {
+			return nil, fmt.Errorf("invalid envelope type %d", t)
+		}
 	}
 
-	if v, ok := q["limit"]; ok {
-		req.Limit, _ = strconv.ParseInt(v[0], 10, 64)
+	return r, nil
+}
+
+// setReadQuery sets the query parameters of the HTTP API from the request.
+// Fields that are not set are omitted, so the LogCache applies its
+// defaults.
+func setReadQuery(q url.Values, r *logcache_v1.ReadRequest) {
+	q.Set("start_time", strconv.FormatInt(r.GetStartTime(), 10))
+
+	if r.GetEndTime() != 0 {
+		q.Set("end_time", strconv.FormatInt(r.GetEndTime(), 10))
 	}
 
-	if v, ok := q["end_time"]; ok {
-		req.EndTime, _ = strconv.ParseInt(v[0], 10, 64)
+	if r.GetLimit() != 0 {
+		q.Set("limit", strconv.FormatInt(r.GetLimit(), 10))
 	}
 
-	for _, et := range q["envelope_types"] {
-		req.EnvelopeTypes = append(req.EnvelopeTypes,
-			logcache_v1.EnvelopeType(logcache_v1.EnvelopeType_value[et]),
-		)
+	for _, t := range r.GetEnvelopeTypes() {
+		q.Add("envelope_types", t.String())
 	}
 
-	if v, ok := q["name_filter"]; ok {
-		req.NameFilter = v[0]
+	if r.GetDescending() {
+		q.Set("descending", "true")
 	}
 
-	if _, ok := q["descending"]; ok {
-		req.Descending = true
+	if r.GetNameFilter() != "" {
+		q.Set("name_filter", r.GetNameFilter())
 	}
+}
 
-	resp, err := c.grpcClient.Read(ctx, req)
+func (c *Client) grpcRead(ctx context.Context, r *logcache_v1.ReadRequest) ([]*loggregator_v2.Envelope, error) {
+	resp, err := c.grpcClient.Read(ctx, r)
 	if err != nil {
-		return nil, err
+		return nil, newGRPCStatusError(ctx, err)
 	}
 	return resp.Envelopes.Batch, nil
 }
 
 // Meta returns meta information from the entire LogCache.
 func (c *Client) Meta(ctx context.Context) (map[string]*logcache_v1.MetaInfo, error) {
+	return withRetry(ctx, c.retryPolicy, func() (map[string]*logcache_v1.MetaInfo, error) {
+		return c.meta(ctx)
+	})
+}
+
+func (c *Client) meta(ctx context.Context) (map[string]*logcache_v1.MetaInfo, error) {
 	if c.grpcClient != nil {
 		return c.grpcMeta(ctx)
 	}
@@ -274,7 +315,7 @@ func (c *Client) Meta(ctx context.Context) (map[string]*logcache_v1.MetaInfo, er
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return nil, newHTTPStatusError(req, resp)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -293,18 +334,22 @@ func (c *Client) Meta(ctx context.Context) (map[string]*logcache_v1.MetaInfo, er
 func (c *Client) grpcMeta(ctx context.Context) (map[string]*logcache_v1.MetaInfo, error) {
 	resp, err := c.grpcClient.Meta(ctx, &logcache_v1.MetaRequest{})
 	if err != nil {
-		return nil, err
+		return nil, newGRPCStatusError(ctx, err)
 	}
 
 	return resp.Meta, nil
 }
 
 func (c *Client) getBaseApiPath(ctx context.Context) (string, error) {
-	if c.baseApiPath != "" {
-		return c.baseApiPath, nil
+	c.baseApiPathMu.Lock()
+	baseApiPath := c.baseApiPath
+	c.baseApiPathMu.Unlock()
+
+	if baseApiPath != "" {
+		return baseApiPath, nil
 	}
 
-	logCacheVersion, err := c.LogCacheVersion(ctx)
+	logCacheVersion, err := c.logCacheVersion(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -314,11 +359,20 @@ func (c *Client) getBaseApiPath(ctx context.Context) (string, error) {
 		apiPath = "/api/v1"
 	}
 
+	c.baseApiPathMu.Lock()
 	c.baseApiPath = apiPath
+	c.baseApiPathMu.Unlock()
+
 	return apiPath, nil
 }
 
 func (c *Client) LogCacheVersion(ctx context.Context) (semver.Version, error) {
+	return withRetry(ctx, c.retryPolicy, func() (semver.Version, error) {
+		return c.logCacheVersion(ctx)
+	})
+}
+
+func (c *Client) logCacheVersion(ctx context.Context) (semver.Version, error) {
 	u, err := url.Parse(c.addr)
 	if err != nil {
 		return semver.Version{}, err
@@ -343,7 +397,7 @@ func (c *Client) LogCacheVersion(ctx context.Context) (semver.Version, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return semver.Version{}, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return semver.Version{}, newHTTPStatusError(req, resp)
 	}
 
 	var info struct {
@@ -359,6 +413,12 @@ func (c *Client) LogCacheVersion(ctx context.Context) (semver.Version, error) {
 }
 
 func (c *Client) LogCacheVMUptime(ctx context.Context) (int64, error) {
+	return withRetry(ctx, c.retryPolicy, func() (int64, error) {
+		return c.logCacheVMUptime(ctx)
+	})
+}
+
+func (c *Client) logCacheVMUptime(ctx context.Context) (int64, error) {
 	u, err := url.Parse(c.addr)
 	if err != nil {
 		return -1, err
@@ -379,7 +439,7 @@ func (c *Client) LogCacheVMUptime(ctx context.Context) (int64, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return -1, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return -1, newHTTPStatusError(req, resp)
 	}
 
 	var info struct {
@@ -403,28 +463,41 @@ func (c *Client) LogCacheVMUptime(ctx context.Context) (int64, error) {
 	return uptime, nil
 }
 
-// PromQLOption configures the URL that is used to submit the query. The
-// RawQuery is set to the decoded query parameters after each option is
-// invoked.
-type PromQLOption func(u *url.URL, q url.Values)
+// PromQLRequest holds the parameters of a PromQL query that are set via
+// PromQLOptions. Both the HTTP and the gRPC transport are derived from it.
+// Instant queries only support Time, range queries only Start, End and
+// Step.
+type PromQLRequest struct {
+	Time  string
+	Start string
+	End   string
+	Step  string
+}
+
+// PromQLOption configures the request that is used to submit the query.
+type PromQLOption func(r *PromQLRequest)
 
 // WithPromQLTime returns a PromQLOption that configures the 'time' query
 // parameter for a PromQL query.
 func WithPromQLTime(t time.Time) PromQLOption {
-	return func(u *url.URL, q url.Values) {
-		q.Set("time", formatDecimalTimeWithMillis(t))
+	return func(r *PromQLRequest) {
+		r.Time = formatDecimalTimeWithMillis(t)
 	}
 }
 
+// WithPromQLStart returns a PromQLOption that configures the 'start' query
+// parameter for a PromQL range query.
 func WithPromQLStart(t time.Time) PromQLOption {
-	return func(u *url.URL, q url.Values) {
-		q.Set("start", formatDecimalTimeWithMillis(t))
+	return func(r *PromQLRequest) {
+		r.Start = formatDecimalTimeWithMillis(t)
 	}
 }
 
+// WithPromQLEnd returns a PromQLOption that configures the 'end' query
+// parameter for a PromQL range query.
 func WithPromQLEnd(t time.Time) PromQLOption {
-	return func(u *url.URL, q url.Values) {
-		q.Set("end", formatDecimalTimeWithMillis(t))
+	return func(r *PromQLRequest) {
+		r.End = formatDecimalTimeWithMillis(t)
 	}
 }
 
@@ -432,10 +505,94 @@ func formatDecimalTimeWithMillis(t time.Time) string {
 	return fmt.Sprintf("%.3f", float64(t.UnixNano())/1e9)
 }
 
+// WithPromQLStep returns a PromQLOption that configures the 'step' query
+// parameter for a PromQL range query, e.g. "5m" or "30".
 func WithPromQLStep(step string) PromQLOption {
-	return func(u *url.URL, q url.Values) {
-		q.Set("step", step)
+	return func(r *PromQLRequest) {
+		r.Step = step
+	}
+}
+
+// newInstantQueryRequest applies the options to an instant query. It returns
+// an error if an option is set that instant queries do not support.
+func newInstantQueryRequest(query string, opts []PromQLOption) (*logcache_v1.PromQL_InstantQueryRequest, error) {
+	var r PromQLRequest
+	for _, o := range opts {
+		o(&r)
+	}
+
+	if r.Start != "" || r.End != "" || r.Step != "" {
+		return nil, errors.New("start, end and step are not supported by instant queries (use PromQLRange)")
+	}
+
+	return &logcache_v1.PromQL_InstantQueryRequest{
+		Query: query,
+		Time:  r.Time,
+	}, nil
+}
+
+// newRangeQueryRequest applies the options to a range query. It returns an
+// error if an option is set that range queries do not support.
+func newRangeQueryRequest(query string, opts []PromQLOption) (*logcache_v1.PromQL_RangeQueryRequest, error) {
+	var r PromQLRequest
+	for _, o := range opts {
+		o(&r)
+	}
+
+	if r.Time != "" {
+		return nil, errors.New("time is not supported by range queries (use PromQL)")
 	}
+
+	return &logcache_v1.PromQL_RangeQueryRequest{
+		Query: query,
+		Start: r.Start,
+		End:   r.End,
+		Step:  r.Step,
+	}, nil
+}
+
+// instantQueryURL returns the URL of the HTTP API for the instant query.
+func (c *Client) instantQueryURL(r *logcache_v1.PromQL_InstantQueryRequest) (string, error) {
+	u, err := url.Parse(c.addr)
+	if err != nil {
+		return "", err
+	}
+	u.Path = "/api/v1/query"
+	q := u.Query()
+	q.Set("query", r.GetQuery())
+
+	if r.GetTime() != "" {
+		q.Set("time", r.GetTime())
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// rangeQueryURL returns the URL of the HTTP API for the range query.
+func (c *Client) rangeQueryURL(r *logcache_v1.PromQL_RangeQueryRequest) (string, error) {
+	u, err := url.Parse(c.addr)
+	if err != nil {
+		return "", err
+	}
+	u.Path = "/api/v1/query_range"
+	q := u.Query()
+	q.Set("query", r.GetQuery())
+
+	if r.GetStart() != "" {
+		q.Set("start", r.GetStart())
+	}
+
+	if r.GetEnd() != "" {
+		q.Set("end", r.GetEnd())
+	}
+
+	if r.GetStep() != "" {
+		q.Set("step", r.GetStep())
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
 }
 
 // PromQL issues a PromQL range query against Log Cache data.
@@ -444,25 +601,36 @@ func (c *Client) PromQLRange(
 	query string,
 	opts ...PromQLOption,
 ) (*logcache_v1.PromQL_RangeQueryResult, error) {
+	r, err := newRangeQueryRequest(query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return withRetry(ctx, c.retryPolicy, func() (*logcache_v1.PromQL_RangeQueryResult, error) {
+		return c.promQLRange(ctx, r)
+	})
+}
+
+func (c *Client) promQLRange(ctx context.Context, r *logcache_v1.PromQL_RangeQueryRequest) (*logcache_v1.PromQL_RangeQueryResult, error) {
 	if c.promqlGrpcClient != nil {
-		return c.grpcPromQLRange(ctx, query, opts)
+		resp, err := c.grpcPromQLRange(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := rangeQueryError(resp); err != nil {
+			return nil, err
+		}
+
+		return resp, nil
 	}
 
-	u, err := url.Parse(c.addr)
+	u, err := c.rangeQueryURL(r)
 	if err != nil {
 		return nil, err
 	}
-	u.Path = "/api/v1/query_range"
-	q := u.Query()
-	q.Set("query", query)
-
-	// allow the given options to configure the URL.
-	for _, o := range opts {
-		o(u, q)
-	}
-	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequest("GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -475,7 +643,7 @@ func (c *Client) PromQLRange(
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return nil, newHTTPStatusError(req, resp)
 	}
 
 	var promQLResponse logcache_v1.PromQL_RangeQueryResult
@@ -484,98 +652,153 @@ func (c *Client) PromQLRange(
 		return nil, err
 	}
 
+	if err := rangeQueryError(&promQLResponse); err != nil {
+		return nil, err
+	}
+
 	return &promQLResponse, nil
 }
 
-func (c *Client) grpcPromQLRange(ctx context.Context, query string, opts []PromQLOption) (*logcache_v1.PromQL_RangeQueryResult, error) {
-	u := &url.URL{}
-	q := u.Query()
-	// allow the given options to configure the URL.
-	for _, o := range opts {
-		o(u, q)
+func (c *Client) grpcPromQLRange(ctx context.Context, r *logcache_v1.PromQL_RangeQueryRequest) (*logcache_v1.PromQL_RangeQueryResult, error) {
+	resp, err := c.promqlGrpcClient.RangeQuery(ctx, r)
+	if err != nil {
+		return nil, newGRPCStatusError(ctx, err)
 	}
+	return resp, nil
+}
 
-	req := &logcache_v1.PromQL_RangeQueryRequest{
-		Query: query,
-	}
+func rangeQueryError(r *logcache_v1.PromQL_RangeQueryResult) error {
+	return newPromQLError(r.GetStatus(), r.GetErrorType(), r.GetError(), r.GetWarnings())
+}
 
-	if v, ok := q["start"]; ok {
-		req.Start = v[0]
-	}
+func instantQueryError(r *logcache_v1.PromQL_InstantQueryResult) error {
+	return newPromQLError(r.GetStatus(), r.GetErrorType(), r.GetError(), r.GetWarnings())
+}
 
-	if v, ok := q["end"]; ok {
-		req.End = v[0]
-	}
+// PromQLRangeSeries issues a PromQL range query against Log Cache data and
+// yields the series of the result one at a time. Unlike PromQLRange, it
+// decodes the HTTP response while it is read, so only a single series is
+// held in memory. Via gRPC, the whole result is received before the first
+// series is yielded.
+func (c *Client) PromQLRangeSeries(
+	ctx context.Context,
+	query string,
+	opts ...PromQLOption,
+) iter.Seq2[*logcache_v1.PromQL_Series, error] {
+	return func(yield func(*logcache_v1.PromQL_Series, error) bool) {
+		r, err := newRangeQueryRequest(query, opts)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
 
-	if v, ok := q["step"]; ok {
-		req.Step = v[0]
-	}
+		if c.promqlGrpcClient != nil {
+			resp, err := withRetry(ctx, c.retryPolicy, func() (*logcache_v1.PromQL_RangeQueryResult, error) {
+				return c.promQLRange(ctx, r)
+			})
+			if err != nil {
+				yield(nil, err)
+				return
+			}
 
-	resp, err := c.promqlGrpcClient.RangeQuery(ctx, req)
-	if err != nil {
-		return nil, err
+			for _, s := range resp.GetMatrix().GetSeries() {
+				if !yield(s, nil) {
+					return
+				}
+			}
+			return
+		}
+
+		body, err := withRetry(ctx, c.retryPolicy, func() (io.ReadCloser, error) {
+			return c.openRangeQuery(ctx, r)
+		})
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer body.Close()
+
+		d := marshaler.NewSeriesDecoder(body)
+		for {
+			s, err := d.Next()
+			if err == io.EOF {
+				if err := newPromQLError(d.Status(), d.ErrorType(), d.Error(), d.Warnings()); err != nil {
+					yield(nil, err)
+				}
+				return
+			}
+
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if !yield(s, nil) {
+				return
+			}
+		}
 	}
-	return resp, nil
 }
 
-func (c *Client) PromQLRangeRaw(
-	ctx context.Context,
-	query string,
-	opts ...PromQLOption,
-) (*PromQLQueryResult, error) {
-	u, err := url.Parse(c.addr)
+// openRangeQuery returns the body of a successful response to the range
+// query.
+func (c *Client) openRangeQuery(ctx context.Context, r *logcache_v1.PromQL_RangeQueryRequest) (io.ReadCloser, error) {
+	u, err := c.rangeQueryURL(r)
 	if err != nil {
 		return nil, err
 	}
-	u.Path = "/api/v1/query_range"
-	q := u.Query()
-	q.Set("query", query)
-
-	// allow the given options to configure the URL.
-	for _, o := range opts {
-		o(u, q)
-	}
-	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
-	req = req.WithContext(ctx)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var result PromQLQueryResult
-
-	// If we got a 404, it's probably due to lack of authorization. Let's try
-	// to be nice to users and give them a hint.
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("unexpected status code %d (check authorization?)", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, newHTTPStatusError(req, resp)
 	}
 
-	// The PromQL API will return nicely-formatted JSON errors with a
-	// status code of either 400 (Bad Request) or 500 (Internal Server Error),
-	// but we can return a generic message for every other status code.
-	if resp.StatusCode != http.StatusOK &&
-		resp.StatusCode != http.StatusBadRequest &&
-		resp.StatusCode != http.StatusInternalServerError {
-		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
-	}
+	return resp.Body, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// PromQLRangeRaw issues a PromQL range query against Log Cache data and
+// returns the result as it is returned by the HTTP API.
+func (c *Client) PromQLRangeRaw(
+	ctx context.Context,
+	query string,
+	opts ...PromQLOption,
+) (*PromQLQueryResult, error) {
+	r, err := newRangeQueryRequest(query, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("%s (status code %d)", err.Error(), resp.StatusCode)
+	return withRetry(ctx, c.retryPolicy, func() (*PromQLQueryResult, error) {
+		return c.promQLRangeRaw(ctx, r)
+	})
+}
+
+func (c *Client) promQLRangeRaw(ctx context.Context, r *logcache_v1.PromQL_RangeQueryRequest) (*PromQLQueryResult, error) {
+	if c.promqlGrpcClient != nil {
+		resp, err := c.grpcPromQLRange(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+
+		return newPromQLQueryResult(resp)
 	}
 
-	return &result, nil
+	u, err := c.rangeQueryURL(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.promQLRawHTTP(ctx, u)
 }
 
 // PromQL issues a PromQL instant query against Log Cache data.
@@ -584,25 +807,36 @@ func (c *Client) PromQL(
 	query string,
 	opts ...PromQLOption,
 ) (*logcache_v1.PromQL_InstantQueryResult, error) {
+	r, err := newInstantQueryRequest(query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return withRetry(ctx, c.retryPolicy, func() (*logcache_v1.PromQL_InstantQueryResult, error) {
+		return c.promQL(ctx, r)
+	})
+}
+
+func (c *Client) promQL(ctx context.Context, r *logcache_v1.PromQL_InstantQueryRequest) (*logcache_v1.PromQL_InstantQueryResult, error) {
 	if c.promqlGrpcClient != nil {
-		return c.grpcPromQL(ctx, query, opts)
+		resp, err := c.grpcPromQL(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := instantQueryError(resp); err != nil {
+			return nil, err
+		}
+
+		return resp, nil
 	}
 
-	u, err := url.Parse(c.addr)
+	u, err := c.instantQueryURL(r)
 	if err != nil {
 		return nil, err
 	}
-	u.Path = "/api/v1/query"
-	q := u.Query()
-	q.Set("query", query)
-
-	// allow the given options to configure the URL.
-	for _, o := range opts {
-		o(u, q)
-	}
-	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequest("GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -616,7 +850,7 @@ func (c *Client) PromQL(
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return nil, newHTTPStatusError(req, resp)
 	}
 
 	var promQLResponse logcache_v1.PromQL_InstantQueryResult
@@ -625,52 +859,58 @@ func (c *Client) PromQL(
 		return nil, err
 	}
 
-	return &promQLResponse, nil
-}
-
-func (c *Client) grpcPromQL(ctx context.Context, query string, opts []PromQLOption) (*logcache_v1.PromQL_InstantQueryResult, error) {
-	u := &url.URL{}
-	q := u.Query()
-	// allow the given options to configure the URL.
-	for _, o := range opts {
-		o(u, q)
-	}
-
-	req := &logcache_v1.PromQL_InstantQueryRequest{
-		Query: query,
+	if err := instantQueryError(&promQLResponse); err != nil {
+		return nil, err
 	}
 
-	if v, ok := q["time"]; ok {
-		req.Time = v[0]
-	}
+	return &promQLResponse, nil
+}
 
-	resp, err := c.promqlGrpcClient.InstantQuery(ctx, req)
+func (c *Client) grpcPromQL(ctx context.Context, r *logcache_v1.PromQL_InstantQueryRequest) (*logcache_v1.PromQL_InstantQueryResult, error) {
+	resp, err := c.promqlGrpcClient.InstantQuery(ctx, r)
 	if err != nil {
-		return nil, err
+		return nil, newGRPCStatusError(ctx, err)
 	}
 	return resp, nil
 }
 
+// PromQLRaw issues a PromQL instant query against Log Cache data and
+// returns the result as it is returned by the HTTP API.
 func (c *Client) PromQLRaw(
 	ctx context.Context,
 	query string,
 	opts ...PromQLOption,
 ) (*PromQLQueryResult, error) {
-	u, err := url.Parse(c.addr)
+	r, err := newInstantQueryRequest(query, opts)
 	if err != nil {
 		return nil, err
 	}
-	u.Path = "/api/v1/query"
-	q := u.Query()
-	q.Set("query", query)
 
-	// allow the given options to configure the URL.
-	for _, o := range opts {
-		o(u, q)
+	return withRetry(ctx, c.retryPolicy, func() (*PromQLQueryResult, error) {
+		return c.promQLRaw(ctx, r)
+	})
+}
+
+func (c *Client) promQLRaw(ctx context.Context, r *logcache_v1.PromQL_InstantQueryRequest) (*PromQLQueryResult, error) {
+	if c.promqlGrpcClient != nil {
+		resp, err := c.grpcPromQL(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+
+		return newPromQLQueryResult(resp)
 	}
-	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	u, err := c.instantQueryURL(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.promQLRawHTTP(ctx, u)
+}
+
+func (c *Client) promQLRawHTTP(ctx context.Context, u string) (*PromQLQueryResult, error) {
+	req, err := http.NewRequest("GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -687,7 +927,9 @@ func (c *Client) PromQLRaw(
 	// If we got a 404, it's probably due to lack of authorization. Let's try
 	// to be nice to users and give them a hint.
 	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("unexpected status code %d (check authorization?)", resp.StatusCode)
+		statusErr := newHTTPStatusError(req, resp)
+		statusErr.hint = "check authorization?"
+		return nil, statusErr
 	}
 
 	// The PromQL API will return nicely-formatted JSON errors with a
@@ -696,7 +938,7 @@ func (c *Client) PromQLRaw(
 	if resp.StatusCode != http.StatusOK &&
 		resp.StatusCode != http.StatusBadRequest &&
 		resp.StatusCode != http.StatusInternalServerError {
-		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		return nil, newHTTPStatusError(req, resp)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -711,11 +953,30 @@ func (c *Client) PromQLRaw(
 	return &result, nil
 }
 
+// newPromQLQueryResult converts the result of a gRPC query into the format
+// of the HTTP API.
+func newPromQLQueryResult(v any) (*PromQLQueryResult, error) {
+	body, err := marshaler.NewPromqlMarshaler(&runtime.JSONPb{}).Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var result PromQLQueryResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// PromQLQueryResult is the response of the PromQL HTTP API. Use AsVector,
+// AsMatrix, AsScalar or AsString to decode the result.
 type PromQLQueryResult struct {
 	Status    string           `json:"status"`
 	Data      PromQLResultData `json:"data"`
 	ErrorType string           `json:"errorType,omitempty"`
 	Error     string           `json:"error,omitempty"`
+	Warnings  []string         `json:"warnings,omitempty"`
 }
 
 type PromQLResultData struct {
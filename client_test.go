@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -19,7 +20,9 @@ import (
 
 	rpc "code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -163,6 +166,22 @@ var _ = Describe("Log Cache Client", func() {
 				Expect(logCache.reqs[1].URL.Query()).To(HaveLen(6))
 			})
 
+			It("returns an error for invalid options without a request", func() {
+				logCache := newStubLogCache()
+				logcache_client := client.NewClient(logCache.addr())
+
+				_, err := logcache_client.Read(context.Background(), "some-id", time.Unix(0, 99), client.WithLimit(-1))
+				Expect(err).To(HaveOccurred())
+
+				_, err = logcache_client.Read(context.Background(), "some-id", time.Unix(0, 99), client.WithEndTime(time.Unix(0, 98)))
+				Expect(err).To(HaveOccurred())
+
+				_, err = logcache_client.Read(context.Background(), "some-id", time.Unix(0, 99), client.WithEnvelopeTypes(rpc.EnvelopeType(99)))
+				Expect(err).To(HaveOccurred())
+
+				Expect(logCache.reqs).To(BeEmpty())
+			})
+
 			It("closes the body", func() {
 				spyHTTPClient := newSpyHTTPClient()
 				logcache_client := client.NewClient("", client.WithHTTPClient(spyHTTPClient))
@@ -180,6 +199,28 @@ var _ = Describe("Log Cache Client", func() {
 				Expect(err).To(HaveOccurred())
 			})
 
+			It("returns a StatusError on a non-200 status", func() {
+				logCache := newStubLogCache()
+				logcache_client := client.NewClient(logCache.addr())
+
+				// Resolve the API path before the stub starts failing.
+				_, err := logcache_client.Read(context.Background(), "some-id", time.Unix(0, 99))
+				Expect(err).ToNot(HaveOccurred())
+
+				logCache.statusCode = http.StatusServiceUnavailable
+				logCache.result["GET/api/v1/read/some-id"] = []byte("some-body")
+
+				_, err = logcache_client.Read(context.Background(), "some-id", time.Unix(0, 99))
+
+				var statusErr *client.StatusError
+				Expect(errors.As(err, &statusErr)).To(BeTrue())
+				Expect(statusErr.StatusCode).To(Equal(http.StatusServiceUnavailable))
+				Expect(statusErr.Code).To(Equal(codes.Unavailable))
+				Expect(statusErr.Body).To(Equal([]byte("some-body")))
+				Expect(statusErr.URL).To(HavePrefix(logCache.addr() + "/api/v1/read/some-id?"))
+				Expect(status.Code(err)).To(Equal(codes.Unavailable))
+			})
+
 			It("returns an error on invalid JSON", func() {
 				logCache := newStubLogCache()
 				logCache.result["GET/api/v1/read/some-id"] = []byte("invalid")
@@ -316,6 +357,19 @@ var _ = Describe("Log Cache Client", func() {
 				Expect(logCache.reqs[0].URL.Query()).To(HaveLen(1))
 			})
 
+			It("returns an error for unsupported options", func() {
+				logCache := newStubLogCache()
+				logcache_client := client.NewClient(logCache.addr())
+
+				_, err := logcache_client.PromQL(
+					context.Background(),
+					"some-query",
+					client.WithPromQLStep("5m"),
+				)
+				Expect(err).To(HaveOccurred())
+				Expect(logCache.reqs).To(BeEmpty())
+			})
+
 			It("respects options", func() {
 				logCache := newStubLogCache()
 				logcache_client := client.NewClient(logCache.addr())
@@ -351,6 +405,39 @@ var _ = Describe("Log Cache Client", func() {
 				Expect(err).To(HaveOccurred())
 			})
 
+			It("returns a StatusError with the PromQL error", func() {
+				logCache := newStubLogCache()
+				logCache.statusCode = http.StatusBadRequest
+				logCache.result["GET/api/v1/query"] = []byte(`{"status":"error","errorType":"bad_data","error":"some-error"}`)
+				logcache_client := client.NewClient(logCache.addr())
+
+				_, err := logcache_client.PromQL(context.Background(), "some-query")
+				Expect(err).To(MatchError("unexpected status code 400: bad_data: some-error"))
+
+				var statusErr *client.StatusError
+				Expect(errors.As(err, &statusErr)).To(BeTrue())
+				Expect(statusErr.ErrorType).To(Equal("bad_data"))
+				Expect(statusErr.Message).To(Equal("some-error"))
+
+				var promQLErr *client.PromQLError
+				Expect(errors.As(err, &promQLErr)).To(BeTrue())
+				Expect(promQLErr.ErrorType).To(Equal("bad_data"))
+			})
+
+			It("returns a PromQLError for an error result", func() {
+				logCache := newStubLogCache()
+				logCache.result["GET/api/v1/query"] = []byte(`{"status":"error","errorType":"execution","error":"some-error","warnings":["some-warning"]}`)
+				logcache_client := client.NewClient(logCache.addr())
+
+				_, err := logcache_client.PromQL(context.Background(), "some-query")
+
+				var promQLErr *client.PromQLError
+				Expect(errors.As(err, &promQLErr)).To(BeTrue())
+				Expect(promQLErr.ErrorType).To(Equal("execution"))
+				Expect(promQLErr.Message).To(Equal("some-error"))
+				Expect(promQLErr.Warnings).To(Equal([]string{"some-warning"}))
+			})
+
 			It("returns an error on invalid JSON", func() {
 				logCache := newStubLogCache()
 				logCache.result["GET/api/v1/query"] = []byte("invalid")
@@ -438,6 +525,19 @@ var _ = Describe("Log Cache Client", func() {
 				Expect(spyHTTPClient.body.closed).To(BeTrue())
 			})
 
+			It("returns a StatusError with a hint on a 404", func() {
+				logCache := newStubLogCache()
+				logCache.statusCode = http.StatusNotFound
+				logcache_client := client.NewClient(logCache.addr())
+
+				_, err := logcache_client.PromQLRaw(context.Background(), "some-query")
+				Expect(err).To(MatchError("unexpected status code 404 (check authorization?)"))
+
+				var statusErr *client.StatusError
+				Expect(errors.As(err, &statusErr)).To(BeTrue())
+				Expect(statusErr.StatusCode).To(Equal(http.StatusNotFound))
+			})
+
 			It("returns an error on a non-200, non-404, non-500 status", func() {
 				logCache := newStubLogCache()
 				logCache.statusCode = 503
@@ -572,6 +672,71 @@ var _ = Describe("Log Cache Client", func() {
 			})
 		})
 
+		Describe("PromQLRangeSeries", func() {
+			It("yields the series", func() {
+				logCache := newStubLogCache()
+				logcache_client := client.NewClient(logCache.addr())
+
+				var series []*rpc.PromQL_Series
+				for s, err := range logcache_client.PromQLRangeSeries(context.Background(), "some-query",
+					client.WithPromQLStep("5m"),
+				) {
+					Expect(err).ToNot(HaveOccurred())
+					series = append(series, s)
+				}
+
+				Expect(series).To(HaveLen(1))
+				Expect(series[0].GetPoints()).To(HaveLen(2))
+				Expect(series[0].GetPoints()[1].Time).To(Equal("5678.000"))
+				Expect(series[0].GetPoints()[1].Value).To(BeEquivalentTo(100))
+
+				Expect(logCache.reqs).To(HaveLen(1))
+				Expect(logCache.reqs[0].URL.Path).To(Equal("/api/v1/query_range"))
+				assertQueryParam(logCache.reqs[0].URL, "step", "5m")
+			})
+
+			It("closes the body when the loop exits early", func() {
+				spyHTTPClient := newSpyHTTPClient()
+				logcache_client := client.NewClient("", client.WithHTTPClient(spyHTTPClient))
+				for range logcache_client.PromQLRangeSeries(context.Background(), "some-query") {
+					break
+				}
+
+				Expect(spyHTTPClient.body.closed).To(BeTrue())
+			})
+
+			It("yields a PromQLError for an error result", func() {
+				logCache := newStubLogCache()
+				logCache.result["GET/api/v1/query_range"] = []byte(`{"status":"error","errorType":"timeout","error":"some-error"}`)
+				logcache_client := client.NewClient(logCache.addr())
+
+				var errs []error
+				for _, err := range logcache_client.PromQLRangeSeries(context.Background(), "some-query") {
+					errs = append(errs, err)
+				}
+
+				Expect(errs).To(HaveLen(1))
+				var promQLErr *client.PromQLError
+				Expect(errors.As(errs[0], &promQLErr)).To(BeTrue())
+				Expect(promQLErr.ErrorType).To(Equal("timeout"))
+			})
+
+			It("yields a StatusError on a non-200 status", func() {
+				logCache := newStubLogCache()
+				logCache.statusCode = http.StatusInternalServerError
+				logcache_client := client.NewClient(logCache.addr())
+
+				var errs []error
+				for _, err := range logcache_client.PromQLRangeSeries(context.Background(), "some-query") {
+					errs = append(errs, err)
+				}
+
+				Expect(errs).To(HaveLen(1))
+				var statusErr *client.StatusError
+				Expect(errors.As(errs[0], &statusErr)).To(BeTrue())
+			})
+		})
+
 		Describe("PromQLRangeRaw", func() {
 			It("retrieves points", func() {
 				logCache := newStubLogCache()
@@ -723,6 +888,20 @@ var _ = Describe("Log Cache Client", func() {
 				)
 				Expect(err).To(HaveOccurred())
 			})
+
+			It("returns a StatusError for a gRPC status", func() {
+				logCache := newStubGrpcLogCache()
+				logCache.err = status.Error(codes.Unauthenticated, "some-error")
+				logcache_client := client.NewClient(logCache.addr(), client.WithViaGRPC(insecureOpt))
+
+				_, err := logcache_client.Read(context.Background(), "some-id", time.Unix(0, 99))
+
+				var statusErr *client.StatusError
+				Expect(errors.As(err, &statusErr)).To(BeTrue())
+				Expect(statusErr.StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(statusErr.Code).To(Equal(codes.Unauthenticated))
+				Expect(statusErr.Message).To(Equal("some-error"))
+			})
 		})
 
 		Describe("Meta", func() {
@@ -790,6 +969,123 @@ var _ = Describe("Log Cache Client", func() {
 				Expect(err).To(HaveOccurred())
 			})
 		})
+
+		Describe("PromQL errors", func() {
+			It("returns a PromQLError for an error result", func() {
+				logCache := newStubGrpcLogCache()
+				logCache.instantResult = &rpc.PromQL_InstantQueryResult{
+					Status:    "error",
+					ErrorType: "bad_data",
+					Error:     "some-error",
+				}
+				logcache_client := client.NewClient(logCache.addr(), client.WithViaGRPC(insecureOpt))
+
+				_, err := logcache_client.PromQL(context.Background(), "some-query")
+				Expect(err).To(MatchError("query failed: bad_data: some-error"))
+
+				var promQLErr *client.PromQLError
+				Expect(errors.As(err, &promQLErr)).To(BeTrue())
+
+				raw, err := logcache_client.PromQLRaw(context.Background(), "some-query")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(raw.Status).To(Equal("error"))
+				Expect(raw.ErrorType).To(Equal("bad_data"))
+				Expect(raw.Error).To(Equal("some-error"))
+			})
+		})
+
+		Describe("PromQLRaw", func() {
+			It("converts the result", func() {
+				logCache := newStubGrpcLogCache()
+				logcache_client := client.NewClient(logCache.addr(), client.WithViaGRPC(insecureOpt))
+
+				result, err := logcache_client.PromQLRaw(context.Background(), "some-query",
+					client.WithPromQLTime(time.Unix(99, 0)),
+				)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(result.Status).To(Equal("success"))
+				Expect(result.Data.ResultType).To(Equal("scalar"))
+				Expect(result.Data.Result).To(MatchJSON(`[99, "101"]`))
+
+				Expect(logCache.promInstantReqs).To(ConsistOf(PointTo(
+					MatchFields(IgnoreExtras,
+						Fields{
+							"Query": Equal("some-query"),
+							"Time":  Equal("99.000"),
+						},
+					),
+				)))
+			})
+		})
+
+		Describe("PromQLRange", func() {
+			It("retrieves points", func() {
+				logCache := newStubGrpcLogCache()
+				logcache_client := client.NewClient(logCache.addr(), client.WithViaGRPC(insecureOpt))
+
+				result, err := logcache_client.PromQLRange(context.Background(), "some-query",
+					client.WithPromQLStart(time.Unix(99, 0)),
+					client.WithPromQLEnd(time.Unix(101, 0)),
+					client.WithPromQLStep("5m"),
+				)
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(result.GetMatrix().GetSeries()).To(HaveLen(1))
+
+				Expect(logCache.promRangeReqs).To(ConsistOf(PointTo(
+					MatchFields(IgnoreExtras,
+						Fields{
+							"Query": Equal("some-query"),
+							"Start": Equal("99.000"),
+							"End":   Equal("101.000"),
+							"Step":  Equal("5m"),
+						},
+					),
+				)))
+			})
+
+			It("returns an error for unsupported options", func() {
+				logCache := newStubGrpcLogCache()
+				logcache_client := client.NewClient(logCache.addr(), client.WithViaGRPC(insecureOpt))
+
+				_, err := logcache_client.PromQLRange(context.Background(), "some-query",
+					client.WithPromQLTime(time.Unix(99, 0)),
+				)
+				Expect(err).To(HaveOccurred())
+				Expect(logCache.promRangeReqs).To(BeEmpty())
+			})
+		})
+
+		Describe("PromQLRangeSeries", func() {
+			It("yields the series", func() {
+				logCache := newStubGrpcLogCache()
+				logcache_client := client.NewClient(logCache.addr(), client.WithViaGRPC(insecureOpt))
+
+				var series []*rpc.PromQL_Series
+				for s, err := range logcache_client.PromQLRangeSeries(context.Background(), "some-query") {
+					Expect(err).ToNot(HaveOccurred())
+					series = append(series, s)
+				}
+
+				Expect(series).To(HaveLen(1))
+				Expect(series[0].GetMetric()).To(HaveKeyWithValue("__name__", "test"))
+			})
+		})
+
+		Describe("PromQLRangeRaw", func() {
+			It("converts the result", func() {
+				logCache := newStubGrpcLogCache()
+				logcache_client := client.NewClient(logCache.addr(), client.WithViaGRPC(insecureOpt))
+
+				result, err := logcache_client.PromQLRangeRaw(context.Background(), "some-query")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(result.Status).To(Equal("success"))
+				Expect(result.Data.ResultType).To(Equal("matrix"))
+				Expect(logCache.promRangeReqs).To(HaveLen(1))
+			})
+		})
 	})
 })
 
@@ -974,6 +1270,8 @@ type stubGrpcLogCache struct {
 	promRangeReqs   []*rpc.PromQL_RangeQueryRequest
 	lis             net.Listener
 	block           bool
+	err             error
+	instantResult   *rpc.PromQL_InstantQueryResult
 	rpc.UnimplementedEgressServer
 	rpc.UnimplementedPromQLQuerierServer
 }
@@ -1006,6 +1304,10 @@ func (s *stubGrpcLogCache) Read(c context.Context, r *rpc.ReadRequest) (*rpc.Rea
 	defer s.mu.Unlock()
 	s.reqs = append(s.reqs, r)
 
+	if s.err != nil {
+		return nil, s.err
+	}
+
 	return &rpc.ReadResponse{
 		Envelopes: &loggregator_v2.EnvelopeBatch{
 			Batch: []*loggregator_v2.Envelope{
@@ -1026,6 +1328,10 @@ func (s *stubGrpcLogCache) InstantQuery(c context.Context, r *rpc.PromQL_Instant
 	defer s.mu.Unlock()
 	s.promInstantReqs = append(s.promInstantReqs, r)
 
+	if s.instantResult != nil {
+		return s.instantResult, nil
+	}
+
 	return &rpc.PromQL_InstantQueryResult{
 		Result: &rpc.PromQL_InstantQueryResult_Scalar{
 			Scalar: &rpc.PromQL_Scalar{
@@ -1082,7 +1388,7 @@ type stubBufferCloser struct {
 }
 
 func newStubBufferCloser() *stubBufferCloser {
-	return &stubBufferCloser{}
+	return &stubBufferCloser{Buffer: &bytes.Buffer{}}
 }
 
 func (s *stubBufferCloser) Close() error {
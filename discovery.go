@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
+)
+
+// MetaReader reads the meta information of every source ID from LogCache.
+// Client.Meta is a MetaReader.
+type MetaReader func(ctx context.Context) (map[string]*logcache_v1.MetaInfo, error)
+
+// SourceEventType is the type of a SourceEvent.
+type SourceEventType int
+
+const (
+	// SourceAdded means that a source ID appeared in LogCache.
+	SourceAdded SourceEventType = iota
+
+	// SourceRemoved means that a source ID disappeared from LogCache, e.g.
+	// because all of its envelopes expired.
+	SourceRemoved
+)
+
+// String implements fmt.Stringer.
+func (t SourceEventType) String() string {
+	switch t {
+	case SourceAdded:
+		return "added"
+	case SourceRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// SourceEvent is yielded by DiscoverSources when the set of source IDs
+// changes.
+type SourceEvent struct {
+	Type     SourceEventType
+	SourceID string
+
+	// Meta holds the count, expired count, oldest and newest timestamp of
+	// the source ID. For SourceRemoved it is the last known meta
+	// information.
+	Meta *logcache_v1.MetaInfo
+}
+
+// DiscoverSources polls Meta and yields an event for every source ID that
+// appears or disappears. See DiscoverSources for details.
+func (c *Client) DiscoverSources(ctx context.Context, opts ...DiscoveryOption) iter.Seq2[SourceEvent, error] {
+	return DiscoverSources(ctx, c.Meta, opts...)
+}
+
+// DiscoverSources polls the MetaReader and yields an event for every source
+// ID that appears or disappears. The first poll yields a SourceAdded event
+// for every existing source ID. Events of a single poll are yielded in the
+// order of their source IDs, with added ones first.
+//
+// Errors from the MetaReader are yielded as well, after which the polling
+// continues. DiscoverSources stops once the context is done or the loop
+// over the iterator is exited.
+func DiscoverSources(ctx context.Context, m MetaReader, opts ...DiscoveryOption) iter.Seq2[SourceEvent, error] {
+	c := discoveryConfig{
+		interval: 10 * time.Second,
+	}
+
+	for _, o := range opts {
+		o.configure(&c)
+	}
+
+	return func(yield func(SourceEvent, error) bool) {
+		if c.glob != "" {
+			if _, err := path.Match(c.glob, ""); err != nil {
+				yield(SourceEvent{}, err)
+				return
+			}
+		}
+
+		t := time.NewTicker(c.interval)
+		defer t.Stop()
+
+		known := make(map[string]*logcache_v1.MetaInfo)
+		for {
+			meta, err := m(ctx)
+			if err != nil && ctx.Err() != nil {
+				return
+			}
+
+			if err != nil {
+				if !yield(SourceEvent{}, err) {
+					return
+				}
+			} else {
+				for _, ev := range c.diff(known, meta) {
+					if !yield(ev, nil) {
+						return
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+		}
+	}
+}
+
+// diff updates known to the given meta information and returns the
+// resulting events.
+func (c *discoveryConfig) diff(known, meta map[string]*logcache_v1.MetaInfo) []SourceEvent {
+	var added, removed []SourceEvent
+
+	for sourceID, m := range meta {
+		if !c.match(sourceID) {
+			continue
+		}
+
+		if _, ok := known[sourceID]; !ok {
+			added = append(added, SourceEvent{Type: SourceAdded, SourceID: sourceID, Meta: m})
+		}
+		known[sourceID] = m
+	}
+
+	for sourceID, m := range known {
+		if _, ok := meta[sourceID]; !ok {
+			removed = append(removed, SourceEvent{Type: SourceRemoved, SourceID: sourceID, Meta: m})
+			delete(known, sourceID)
+		}
+	}
+
+	bySourceID := func(a, b SourceEvent) int {
+		return strings.Compare(a.SourceID, b.SourceID)
+	}
+	slices.SortFunc(added, bySourceID)
+	slices.SortFunc(removed, bySourceID)
+
+	return append(added, removed...)
+}
+
+func (c *discoveryConfig) match(sourceID string) bool {
+	if c.glob != "" {
+		if ok, _ := path.Match(c.glob, sourceID); !ok {
+			return false
+		}
+	}
+
+	if c.regexp != nil && !c.regexp.MatchString(sourceID) {
+		return false
+	}
+
+	return true
+}
+
+// DiscoveryOption configures DiscoverSources.
+type DiscoveryOption interface {
+	configure(c *discoveryConfig)
+}
+
+// WithDiscoveryInterval sets the interval Meta is polled at. It defaults to
+// 10 seconds, which is also used if the interval is not positive.
+func WithDiscoveryInterval(d time.Duration) DiscoveryOption {
+	return discoveryOptionFunc(func(c *discoveryConfig) {
+		if d > 0 {
+			c.interval = d
+		}
+	})
+}
+
+// WithDiscoveryGlob only yields events for the source IDs that match the
+// given pattern. See path.Match for the syntax.
+func WithDiscoveryGlob(pattern string) DiscoveryOption {
+	return discoveryOptionFunc(func(c *discoveryConfig) {
+		c.glob = pattern
+	})
+}
+
+// WithDiscoveryRegexp only yields events for the source IDs that match the
+// given regular expression.
+func WithDiscoveryRegexp(re *regexp.Regexp) DiscoveryOption {
+	return discoveryOptionFunc(func(c *discoveryConfig) {
+		c.regexp = re
+	})
+}
+
+type discoveryConfig struct {
+	interval time.Duration
+	glob     string
+	regexp   *regexp.Regexp
+}
+
+// discoveryOptionFunc enables functions to implement DiscoveryOption.
+type discoveryOptionFunc func(c *discoveryConfig)
+
+// configure implements DiscoveryOption.
+func (f discoveryOptionFunc) configure(c *discoveryConfig) {
+	f(c)
+}
@@ -0,0 +1,191 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	client "code.cloudfoundry.org/go-log-cache/v3"
+	rpc "code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
+)
+
+func TestDiscoverSourcesYieldsAddedAndRemovedSources(t *testing.T) {
+	t.Parallel()
+
+	m := &stubMetaReader{
+		metas: []map[string]*rpc.MetaInfo{
+			{"a": {Count: 1}, "b": {Count: 2}},
+			{"b": {Count: 3}, "c": {Count: 4, NewestTimestamp: 5}},
+		},
+	}
+
+	var events []client.SourceEvent
+	for ev, err := range client.DiscoverSources(context.Background(), m.read, client.WithDiscoveryInterval(time.Millisecond)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		events = append(events, ev)
+		if len(events) == 4 {
+			break
+		}
+	}
+
+	expected := []struct {
+		typ      client.SourceEventType
+		sourceID string
+		count    int64
+	}{
+		{client.SourceAdded, "a", 1},
+		{client.SourceAdded, "b", 2},
+		{client.SourceAdded, "c", 4},
+		{client.SourceRemoved, "a", 1},
+	}
+
+	for i, e := range expected {
+		ev := events[i]
+		if ev.Type != e.typ || ev.SourceID != e.sourceID || ev.Meta.GetCount() != e.count {
+			t.Fatalf("wrong event %d: %s %s %d", i, ev.Type, ev.SourceID, ev.Meta.GetCount())
+		}
+	}
+
+	if events[2].Meta.GetNewestTimestamp() != 5 {
+		t.Fatalf("expected meta information to be passed: %v", events[2].Meta)
+	}
+}
+
+func TestDiscoverSourcesFilters(t *testing.T) {
+	t.Parallel()
+
+	m := &stubMetaReader{
+		metas: []map[string]*rpc.MetaInfo{
+			{"app-1": {}, "app-2": {}, "app-10": {}, "system": {}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		opt      client.DiscoveryOption
+		expected []string
+	}{
+		{"glob", client.WithDiscoveryGlob("app-?"), []string{"app-1", "app-2"}},
+		{"regexp", client.WithDiscoveryRegexp(regexp.MustCompile(`^app-\d+$`)), []string{"app-1", "app-10", "app-2"}},
+	}
+
+	for _, tt := range tests {
+		var sourceIDs []string
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		for ev, err := range client.DiscoverSources(ctx, m.read, tt.opt, client.WithDiscoveryInterval(time.Millisecond)) {
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sourceIDs = append(sourceIDs, ev.SourceID)
+		}
+		cancel()
+
+		if len(sourceIDs) != len(tt.expected) {
+			t.Fatalf("%s: wrong source IDs: %v", tt.name, sourceIDs)
+		}
+
+		for i := range sourceIDs {
+			if sourceIDs[i] != tt.expected[i] {
+				t.Fatalf("%s: wrong source IDs: %v", tt.name, sourceIDs)
+			}
+		}
+	}
+}
+
+func TestDiscoverSourcesYieldsErrors(t *testing.T) {
+	t.Parallel()
+
+	m := &stubMetaReader{
+		metas: []map[string]*rpc.MetaInfo{nil, {"a": {}}},
+		errs:  []error{errors.New("some-error"), nil},
+	}
+
+	var (
+		errs   []error
+		events []client.SourceEvent
+	)
+	for ev, err := range client.DiscoverSources(context.Background(), m.read, client.WithDiscoveryInterval(time.Millisecond)) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		events = append(events, ev)
+		break
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error: %v", errs)
+	}
+
+	if len(events) != 1 || events[0].SourceID != "a" {
+		t.Fatalf("expected polling to continue after an error: %v", events)
+	}
+}
+
+func TestDiscoverSourcesIgnoresNonPositiveInterval(t *testing.T) {
+	t.Parallel()
+
+	for _, d := range []time.Duration{0, -time.Second} {
+		m := &stubMetaReader{
+			metas: []map[string]*rpc.MetaInfo{{"a": {}}},
+			errs:  []error{nil},
+		}
+
+		var events []client.SourceEvent
+		for ev, err := range client.DiscoverSources(context.Background(), m.read, client.WithDiscoveryInterval(d)) {
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			events = append(events, ev)
+			break
+		}
+
+		if len(events) != 1 || events[0].SourceID != "a" {
+			t.Fatalf("expected the default interval for %s: %v", d, events)
+		}
+	}
+}
+
+func TestDiscoverSourcesRejectsInvalidGlob(t *testing.T) {
+	t.Parallel()
+
+	m := &stubMetaReader{}
+
+	var errs []error
+	for _, err := range client.DiscoverSources(context.Background(), m.read, client.WithDiscoveryGlob("[")) {
+		errs = append(errs, err)
+	}
+
+	if len(errs) != 1 || errs[0] == nil {
+		t.Fatalf("expected an error: %v", errs)
+	}
+}
+
+// stubMetaReader returns the given metas and errs in order and repeats the
+// last one.
+type stubMetaReader struct {
+	metas []map[string]*rpc.MetaInfo
+	errs  []error
+	calls int
+}
+
+func (s *stubMetaReader) read(ctx context.Context) (map[string]*rpc.MetaInfo, error) {
+	i := min(s.calls, len(s.metas)-1)
+	s.calls++
+
+	var err error
+	if i < len(s.errs) {
+		err = s.errs[i]
+	}
+
+	return s.metas[i], err
+}
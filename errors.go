@@ -0,0 +1,254 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxErrorBodyBytes limits how much of an unexpected response is kept on a
+// StatusError.
+const maxErrorBodyBytes = 64 * 1024
+
+// StatusError is returned when LogCache responds with an unexpected status.
+// Errors from the gRPC transport (see WithViaGRPC) are mapped onto the
+// equivalent HTTP status code, so both transports can be handled the same
+// way, e.g.:
+//
+//	var statusErr *client.StatusError
+//	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+//		...
+//	}
+type StatusError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Code is the gRPC status code. For HTTP responses it is derived from
+	// StatusCode.
+	Code codes.Code
+
+	// URL is the URL of the request. It is empty for gRPC requests.
+	URL string
+
+	// Body is the (possibly truncated) body of the HTTP response. For gRPC
+	// requests it is the message of the status.
+	Body []byte
+
+	// ErrorType and Message are the 'errorType' and 'error' fields of a
+	// PromQL error response. Message is also set to the message of a gRPC
+	// status.
+	ErrorType string
+	Message   string
+
+	// RetryAfter is the delay requested by the 'Retry-After' header of the
+	// HTTP response. It is zero if the header is absent.
+	RetryAfter time.Duration
+
+	hint string
+}
+
+// Error implements error.
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("unexpected status code %d", e.StatusCode)
+
+	switch {
+	case e.ErrorType != "" && e.Message != "":
+		msg = fmt.Sprintf("%s: %s: %s", msg, e.ErrorType, e.Message)
+	case e.Message != "":
+		msg = fmt.Sprintf("%s: %s", msg, e.Message)
+	}
+
+	if e.hint != "" {
+		msg = fmt.Sprintf("%s (%s)", msg, e.hint)
+	}
+
+	return msg
+}
+
+// Unwrap returns a PromQLError if the response is a PromQL error response,
+// so errors.As can be used to handle query errors regardless of whether
+// they are reported via the status code or the result.
+func (e *StatusError) Unwrap() error {
+	if e.ErrorType == "" {
+		return nil
+	}
+
+	return &PromQLError{
+		ErrorType: e.ErrorType,
+		Message:   e.Message,
+	}
+}
+
+// GRPCStatus returns the gRPC status that is equivalent to the error. It
+// enables status.FromError and status.Code to be used with a StatusError.
+func (e *StatusError) GRPCStatus() *status.Status {
+	return status.New(e.Code, e.Error())
+}
+
+// newHTTPStatusError builds a StatusError from an unexpected response. It
+// consumes the body of the response.
+func newHTTPStatusError(req *http.Request, resp *http.Response) *StatusError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+
+	e := &StatusError{
+		StatusCode: resp.StatusCode,
+		Code:       codeFromHTTPStatus(resp.StatusCode),
+		URL:        req.URL.String(),
+		Body:       body,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+
+	var promQLErr struct {
+		ErrorType string `json:"errorType"`
+		Error     string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &promQLErr); err == nil {
+		e.ErrorType = promQLErr.ErrorType
+		e.Message = promQLErr.Error
+	}
+
+	return e
+}
+
+// PromQLError is returned by PromQL and PromQLRange when the result of a
+// query has the status "error", e.g. because the query is invalid.
+type PromQLError struct {
+	// ErrorType is the 'errorType' field of the result, e.g. "bad_data".
+	ErrorType string
+
+	// Message is the 'error' field of the result.
+	Message string
+
+	// Warnings are the 'warnings' field of the result.
+	Warnings []string
+}
+
+// Error implements error.
+func (e *PromQLError) Error() string {
+	if e.ErrorType == "" {
+		return fmt.Sprintf("query failed: %s", e.Message)
+	}
+
+	return fmt.Sprintf("query failed: %s: %s", e.ErrorType, e.Message)
+}
+
+// newPromQLError returns a PromQLError if the status of a result is "error".
+func newPromQLError(status, errorType, message string, warnings []string) error {
+	if status != "error" {
+		return nil
+	}
+
+	return &PromQLError{
+		ErrorType: errorType,
+		Message:   message,
+		Warnings:  warnings,
+	}
+}
+
+// parseRetryAfter supports both the delay-seconds and the HTTP-date form of
+// the 'Retry-After' header.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
+
+// newGRPCStatusError converts an error returned by a gRPC call into a
+// StatusError. Errors caused by the given context, as well as errors that do
+// not carry a gRPC status, are returned as they are.
+func newGRPCStatusError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	return &StatusError{
+		StatusCode: httpStatusFromCode(s.Code()),
+		Code:       s.Code(),
+		Body:       []byte(s.Message()),
+		Message:    s.Message(),
+	}
+}
+
+// httpStatusFromCode follows the mapping of grpc-gateway, which serves the
+// HTTP API of LogCache.
+func httpStatusFromCode(c codes.Code) int {
+	switch c {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func codeFromHTTPStatus(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusOK:
+		return codes.OK
+	case 499:
+		return codes.Canceled
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Unknown
+	}
+}
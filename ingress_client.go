@@ -0,0 +1,285 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
+
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// defaultIngressMaxBatchBytes keeps batches well below gRPC's default
+	// 4MB message size limit.
+	defaultIngressMaxBatchBytes = 1024 * 1024
+
+	defaultIngressFlushInterval = time.Second
+
+	// defaultIngressFlushTimeout matches the default timeout of the HTTP
+	// client used by Client.
+	defaultIngressFlushTimeout = 5 * time.Second
+)
+
+// IngressClient writes envelopes to LogCache via gRPC.
+type IngressClient struct {
+	conn       *grpc.ClientConn
+	grpcClient logcache_v1.IngressClient
+
+	dialOpts      []grpc.DialOption
+	localOnly     bool
+	maxBatchBytes int
+	flushInterval time.Duration
+	log           *log.Logger
+
+	mu      sync.Mutex
+	buf     []*loggregator_v2.Envelope
+	bufSize int
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+// NewIngressClient creates an IngressClient.
+func NewIngressClient(addr string, opts ...IngressOption) *IngressClient {
+	c := &IngressClient{
+		maxBatchBytes: defaultIngressMaxBatchBytes,
+		flushInterval: defaultIngressFlushInterval,
+		log:           log.New(io.Discard, "", 0),
+		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+
+	for _, o := range opts {
+		o.configure(c)
+	}
+
+	conn, err := grpc.NewClient(addr, c.dialOpts...)
+	if err != nil {
+		panic(fmt.Sprintf("failed to dial via gRPC: %s", err))
+	}
+	c.conn = conn
+	c.grpcClient = logcache_v1.NewIngressClient(conn)
+
+	return c
+}
+
+// IngressOption configures the IngressClient.
+type IngressOption interface {
+	configure(c *IngressClient)
+}
+
+// WithIngressDialOptions sets the gRPC dial options used to connect to
+// LogCache.
+func WithIngressDialOptions(opts ...grpc.DialOption) IngressOption {
+	return ingressOptionFunc(func(c *IngressClient) {
+		c.dialOpts = append(c.dialOpts, opts...)
+	})
+}
+
+// WithIngressLocalOnly sets 'local_only' on every request. LogCache will then
+// store the envelopes on the receiving node instead of routing them to the
+// node that owns the source ID.
+func WithIngressLocalOnly() IngressOption {
+	return ingressOptionFunc(func(c *IngressClient) {
+		c.localOnly = true
+	})
+}
+
+// WithIngressMaxBatchBytes sets the maximum marshaled size of a single
+// EnvelopeBatch. Envelopes are split across several requests to honor it. An
+// envelope that is larger than the limit on its own is sent by itself. It
+// defaults to 1MB.
+func WithIngressMaxBatchBytes(n int) IngressOption {
+	return ingressOptionFunc(func(c *IngressClient) {
+		c.maxBatchBytes = n
+	})
+}
+
+// WithIngressFlushInterval sets how often envelopes buffered by Emit are
+// sent. It defaults to a second, which is also used if the interval is not
+// positive.
+func WithIngressFlushInterval(d time.Duration) IngressOption {
+	return ingressOptionFunc(func(c *IngressClient) {
+		if d > 0 {
+			c.flushInterval = d
+		}
+	})
+}
+
+// WithIngressLogger is used to set the logger for errors that occur while
+// flushing envelopes in the background. It defaults to not logging.
+func WithIngressLogger(l *log.Logger) IngressOption {
+	return ingressOptionFunc(func(c *IngressClient) {
+		c.log = l
+	})
+}
+
+// ingressOptionFunc enables a function to be an IngressOption.
+type ingressOptionFunc func(c *IngressClient)
+
+// configure implements IngressOption.
+func (f ingressOptionFunc) configure(c *IngressClient) {
+	f(c)
+}
+
+// Send writes the given envelopes to LogCache. The envelopes are split into
+// batches that honor the configured maximum batch size and are sent in
+// order. Send returns once every batch has been stored or the first batch
+// fails.
+func (c *IngressClient) Send(ctx context.Context, envelopes ...*loggregator_v2.Envelope) error {
+	for _, batch := range c.batches(envelopes) {
+		if err := c.send(ctx, batch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Emit buffers the given envelope. Buffered envelopes are sent once they
+// fill a batch or the flush interval elapses, whichever happens first.
+// Errors are written to the configured logger.
+func (c *IngressClient) Emit(e *loggregator_v2.Envelope) {
+	c.startOnce.Do(func() {
+		go c.flushLoop()
+	})
+
+	size := envelopeBatchEntrySize(e)
+
+	c.mu.Lock()
+	var full []*loggregator_v2.Envelope
+	if len(c.buf) > 0 && c.bufSize+size > c.maxBatchBytes {
+		full = c.takeBuffer()
+	}
+	c.buf = append(c.buf, e)
+	c.bufSize += size
+	c.mu.Unlock()
+
+	if full != nil {
+		c.flushBatch(full)
+	}
+}
+
+// Flush sends every envelope buffered by Emit.
+func (c *IngressClient) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	es := c.takeBuffer()
+	c.mu.Unlock()
+
+	return c.Send(ctx, es...)
+}
+
+// Close stops the background flushing started by Emit, sends any envelopes
+// that are still buffered and closes the connection to LogCache. The
+// IngressClient must not be used after Close is invoked: Send and another
+// Close return an error.
+func (c *IngressClient) Close(ctx context.Context) error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+
+		started := true
+		c.startOnce.Do(func() {
+			started = false
+		})
+
+		if started {
+			<-c.stopped
+		}
+	})
+
+	return errors.Join(c.Flush(ctx), c.conn.Close())
+}
+
+func (c *IngressClient) flushLoop() {
+	defer close(c.stopped)
+
+	ticker := time.NewTicker(c.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			es := c.takeBuffer()
+			c.mu.Unlock()
+
+			for _, batch := range c.batches(es) {
+				c.flushBatch(batch)
+			}
+		}
+	}
+}
+
+func (c *IngressClient) flushBatch(es []*loggregator_v2.Envelope) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultIngressFlushTimeout)
+	defer cancel()
+
+	if err := c.send(ctx, es); err != nil {
+		c.log.Printf("failed to send %d envelopes: %s", len(es), err)
+	}
+}
+
+// takeBuffer must be invoked while holding c.mu.
+func (c *IngressClient) takeBuffer() []*loggregator_v2.Envelope {
+	es := c.buf
+	c.buf = nil
+	c.bufSize = 0
+	return es
+}
+
+func (c *IngressClient) send(ctx context.Context, es []*loggregator_v2.Envelope) error {
+	_, err := c.grpcClient.Send(ctx, &logcache_v1.SendRequest{
+		Envelopes: &loggregator_v2.EnvelopeBatch{
+			Batch: es,
+		},
+		LocalOnly: c.localOnly,
+	})
+
+	return err
+}
+
+func (c *IngressClient) batches(es []*loggregator_v2.Envelope) [][]*loggregator_v2.Envelope {
+	var (
+		batches [][]*loggregator_v2.Envelope
+		batch   []*loggregator_v2.Envelope
+		size    int
+	)
+
+	for _, e := range es {
+		s := envelopeBatchEntrySize(e)
+		if len(batch) > 0 && size+s > c.maxBatchBytes {
+			batches = append(batches, batch)
+			batch = nil
+			size = 0
+		}
+
+		batch = append(batch, e)
+		size += s
+	}
+
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
+
+// envelopeBatchEntrySize returns the number of bytes the envelope occupies
+// within a marshaled EnvelopeBatch: the field tag, the length prefix and the
+// envelope itself.
+func envelopeBatchEntrySize(e *loggregator_v2.Envelope) int {
+	return protowire.SizeTag(1) + protowire.SizeBytes(proto.Size(e))
+}
@@ -0,0 +1,270 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	client "code.cloudfoundry.org/go-log-cache/v3"
+
+	rpc "code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestIngressClientSend(t *testing.T) {
+	t.Parallel()
+
+	s := newStubIngress(t)
+	c := client.NewIngressClient(s.addr(), client.WithIngressDialOptions(insecureOpt))
+
+	err := c.Send(context.Background(),
+		&loggregator_v2.Envelope{SourceId: "some-id", Timestamp: 1},
+		&loggregator_v2.Envelope{SourceId: "some-id", Timestamp: 2},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := s.requests()
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request: %d", len(reqs))
+	}
+
+	if reqs[0].GetLocalOnly() {
+		t.Fatal("expected local_only to be false")
+	}
+
+	batch := reqs[0].GetEnvelopes().GetBatch()
+	if len(batch) != 2 || batch[0].Timestamp != 1 || batch[1].Timestamp != 2 {
+		t.Fatalf("wrong envelopes: %v", batch)
+	}
+}
+
+func TestIngressClientSendLocalOnly(t *testing.T) {
+	t.Parallel()
+
+	s := newStubIngress(t)
+	c := client.NewIngressClient(s.addr(),
+		client.WithIngressDialOptions(insecureOpt),
+		client.WithIngressLocalOnly(),
+	)
+
+	err := c.Send(context.Background(), &loggregator_v2.Envelope{SourceId: "some-id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := s.requests()
+	if len(reqs) != 1 || !reqs[0].GetLocalOnly() {
+		t.Fatal("expected local_only to be true")
+	}
+}
+
+func TestIngressClientSendSplitsBatchesBySize(t *testing.T) {
+	t.Parallel()
+
+	var es []*loggregator_v2.Envelope
+	for i := 0; i < 10; i++ {
+		es = append(es, &loggregator_v2.Envelope{
+			SourceId:  "some-id",
+			Timestamp: int64(i + 1),
+			Message: &loggregator_v2.Envelope_Log{
+				Log: &loggregator_v2.Log{Payload: make([]byte, 100)},
+			},
+		})
+	}
+
+	maxBatchBytes := 3 * proto.Size(&loggregator_v2.EnvelopeBatch{Batch: es[:1]})
+
+	s := newStubIngress(t)
+	c := client.NewIngressClient(s.addr(),
+		client.WithIngressDialOptions(insecureOpt),
+		client.WithIngressMaxBatchBytes(maxBatchBytes),
+	)
+
+	if err := c.Send(context.Background(), es...); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := s.requests()
+	if len(reqs) != 4 {
+		t.Fatalf("expected 4 requests: %d", len(reqs))
+	}
+
+	var ts []int64
+	for _, r := range reqs {
+		if size := proto.Size(r.GetEnvelopes()); size > maxBatchBytes {
+			t.Fatalf("expected batch size %d to be at most %d", size, maxBatchBytes)
+		}
+
+		for _, e := range r.GetEnvelopes().GetBatch() {
+			ts = append(ts, e.Timestamp)
+		}
+	}
+
+	for i, x := range ts {
+		if x != int64(i+1) {
+			t.Fatalf("expected envelopes to be sent in order: %v", ts)
+		}
+	}
+}
+
+func TestIngressClientSendReturnsError(t *testing.T) {
+	t.Parallel()
+
+	s := newStubIngress(t)
+	s.err = errors.New("some-error")
+	c := client.NewIngressClient(s.addr(), client.WithIngressDialOptions(insecureOpt))
+
+	err := c.Send(context.Background(), &loggregator_v2.Envelope{SourceId: "some-id"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestIngressClientEmitFlushesOnInterval(t *testing.T) {
+	t.Parallel()
+
+	s := newStubIngress(t)
+	c := client.NewIngressClient(s.addr(),
+		client.WithIngressDialOptions(insecureOpt),
+		client.WithIngressFlushInterval(10*time.Millisecond),
+	)
+	defer c.Close(context.Background()) //nolint:errcheck
+
+	c.Emit(&loggregator_v2.Envelope{SourceId: "some-id", Timestamp: 1})
+	c.Emit(&loggregator_v2.Envelope{SourceId: "some-id", Timestamp: 2})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for s.envelopeCount() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 2 envelopes to be flushed: %d", s.envelopeCount())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestIngressClientIgnoresNonPositiveFlushInterval(t *testing.T) {
+	t.Parallel()
+
+	s := newStubIngress(t)
+	c := client.NewIngressClient(s.addr(),
+		client.WithIngressDialOptions(insecureOpt),
+		client.WithIngressFlushInterval(0),
+	)
+
+	c.Emit(&loggregator_v2.Envelope{SourceId: "some-id", Timestamp: 1})
+
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.envelopeCount() != 1 {
+		t.Fatalf("expected buffered envelope to be flushed: %d", s.envelopeCount())
+	}
+}
+
+func TestIngressClientCloseFlushesBuffer(t *testing.T) {
+	t.Parallel()
+
+	s := newStubIngress(t)
+	c := client.NewIngressClient(s.addr(),
+		client.WithIngressDialOptions(insecureOpt),
+		client.WithIngressFlushInterval(time.Hour),
+	)
+
+	c.Emit(&loggregator_v2.Envelope{SourceId: "some-id", Timestamp: 1})
+
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.envelopeCount() != 1 {
+		t.Fatalf("expected buffered envelope to be flushed: %d", s.envelopeCount())
+	}
+}
+
+func TestIngressClientCloseClosesConnection(t *testing.T) {
+	t.Parallel()
+
+	s := newStubIngress(t)
+	c := client.NewIngressClient(s.addr(), client.WithIngressDialOptions(insecureOpt))
+
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Close(context.Background()); err == nil {
+		t.Fatal("expected a second Close to fail")
+	}
+
+	err := c.Send(context.Background(), &loggregator_v2.Envelope{SourceId: "some-id"})
+	if status.Code(err) != codes.Canceled {
+		t.Fatalf("expected Send to fail on the closed connection: %v", err)
+	}
+
+	if s.envelopeCount() != 0 {
+		t.Fatalf("expected no envelopes to be sent: %d", s.envelopeCount())
+	}
+}
+
+type stubIngress struct {
+	mu   sync.Mutex
+	reqs []*rpc.SendRequest
+	err  error
+	lis  net.Listener
+	rpc.UnimplementedIngressServer
+}
+
+func newStubIngress(t *testing.T) *stubIngress {
+	s := &stubIngress{}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.lis = lis
+	srv := grpc.NewServer()
+	rpc.RegisterIngressServer(srv, s)
+	go srv.Serve(lis) //nolint:errcheck
+	t.Cleanup(srv.Stop)
+
+	return s
+}
+
+func (s *stubIngress) addr() string {
+	return s.lis.Addr().String()
+}
+
+func (s *stubIngress) Send(ctx context.Context, r *rpc.SendRequest) (*rpc.SendResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reqs = append(s.reqs, r)
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &rpc.SendResponse{}, nil
+}
+
+func (s *stubIngress) requests() []*rpc.SendRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*rpc.SendRequest(nil), s.reqs...)
+}
+
+func (s *stubIngress) envelopeCount() int {
+	var n int
+	for _, r := range s.requests() {
+		n += len(r.GetEnvelopes().GetBatch())
+	}
+	return n
+}
@@ -1,10 +1,14 @@
 package internal
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"strconv"
+	"strings"
 
 	"code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
 
@@ -47,10 +51,11 @@ func appendNewLine(bytes []byte, err error) ([]byte, error) {
 }
 
 type queryResult struct {
-	Status    string     `json:"status"`
-	Data      resultData `json:"data"`
-	ErrorType string     `json:"errorType,omitempty"`
-	Error     string     `json:"error,omitempty"`
+	Status    string      `json:"status"`
+	Data      *resultData `json:"data,omitempty"`
+	ErrorType string      `json:"errorType,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	Warnings  []string    `json:"warnings,omitempty"`
 }
 
 type resultData struct {
@@ -69,6 +74,11 @@ type series struct {
 }
 
 func (m *PromqlMarshaler) assembleInstantQueryResult(v *logcache_v1.PromQL_InstantQueryResult) (*queryResult, error) {
+	result := newQueryResult(v.GetStatus(), v.GetErrorType(), v.GetError(), v.GetWarnings())
+	if result.Status == "error" {
+		return result, nil
+	}
+
 	var data resultData
 	var err error
 
@@ -79,19 +89,24 @@ func (m *PromqlMarshaler) assembleInstantQueryResult(v *logcache_v1.PromQL_Insta
 		data, err = assembleVectorResultData(v.GetVector())
 	case *logcache_v1.PromQL_InstantQueryResult_Matrix:
 		data, err = assembleMatrixResultData(v.GetMatrix())
+	case *logcache_v1.PromQL_InstantQueryResult_StringValue:
+		data, err = assembleStringResultData(v.GetStringValue())
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &queryResult{
-		Status: "success",
-		Data:   data,
-	}, nil
+	result.Data = &data
+	return result, nil
 }
 
 func (m *PromqlMarshaler) assembleRangeQueryResult(v *logcache_v1.PromQL_RangeQueryResult) (*queryResult, error) {
+	result := newQueryResult(v.GetStatus(), v.GetErrorType(), v.GetError(), v.GetWarnings())
+	if result.Status == "error" {
+		return result, nil
+	}
+
 	var data resultData
 	var err error
 
@@ -104,9 +119,42 @@ func (m *PromqlMarshaler) assembleRangeQueryResult(v *logcache_v1.PromQL_RangeQu
 		return nil, err
 	}
 
+	result.Data = &data
+	return result, nil
+}
+
+// newQueryResult defaults the status to "success" for results that do not
+// set it.
+func newQueryResult(status, errorType, err string, warnings []string) *queryResult {
+	if status == "" {
+		status = "success"
+	}
+
 	return &queryResult{
-		Status: "success",
-		Data:   data,
+		Status:    status,
+		ErrorType: errorType,
+		Error:     err,
+		Warnings:  warnings,
+	}
+}
+
+func assembleStringResultData(v *logcache_v1.PromQL_String) (resultData, error) {
+	t, err := formatTimestamp(v.GetTime())
+	if err != nil {
+		return resultData{}, err
+	}
+
+	data, err := json.Marshal([]interface{}{
+		t,
+		v.GetValue(),
+	})
+	if err != nil {
+		return resultData{}, err
+	}
+
+	return resultData{
+		ResultType: "string",
+		Result:     data,
 	}, nil
 }
 
@@ -200,17 +248,120 @@ func assembleMatrixResultData(v *logcache_v1.PromQL_Matrix) (resultData, error)
 }
 
 func assemblePoint(time string, value float64) ([]interface{}, error) {
-	t, err := strconv.ParseFloat(time, 64)
+	t, err := formatTimestamp(time)
 	if err != nil {
-		return nil, fmt.Errorf("couldn't parse float %s: %s", time, err.Error())
+		return nil, err
 	}
-	formattedTime := fmt.Sprintf("%.3f", t)
+
 	return []interface{}{
-		json.RawMessage(formattedTime),
-		strconv.FormatFloat(value, 'f', -1, 64),
+		t,
+		formatValue(value),
 	}, nil
 }
 
+// formatTimestamp formats a timestamp in seconds like Prometheus: as a JSON
+// number with three decimal places, or none for whole seconds, e.g. 1.500 or
+// 1. Unlike Prometheus, it keeps any sub-millisecond precision of the given
+// string.
+func formatTimestamp(t string) (json.RawMessage, error) {
+	sign, secs, frac, ok := splitDecimal(t)
+	if !ok {
+		f, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse float %s: %s", t, err.Error())
+		}
+
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("invalid timestamp %s", t)
+		}
+
+		sign, secs, frac, _ = splitDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	}
+
+	frac = strings.TrimRight(frac, "0")
+	if secs == "0" && frac == "" {
+		sign = ""
+	}
+
+	if frac != "" {
+		if len(frac) < 3 {
+			frac += strings.Repeat("0", 3-len(frac))
+		}
+		return json.RawMessage(sign + secs + "." + frac), nil
+	}
+
+	return json.RawMessage(sign + secs), nil
+}
+
+// parseTimestamp returns the timestamp of a point in the format of the
+// protobuf results: seconds with at least three decimal places, e.g.
+// "1.500" or "1.234567".
+func parseTimestamp(n json.Number) (string, error) {
+	sign, secs, frac, ok := splitDecimal(n.String())
+	if !ok {
+		f, err := n.Float64()
+		if err != nil {
+			return "", fmt.Errorf("couldn't parse float %s: %s", n, err.Error())
+		}
+
+		sign, secs, frac, _ = splitDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	}
+
+	frac = strings.TrimRight(frac, "0")
+	if secs == "0" && frac == "" {
+		sign = ""
+	}
+
+	if len(frac) < 3 {
+		frac += strings.Repeat("0", 3-len(frac))
+	}
+
+	return sign + secs + "." + frac, nil
+}
+
+// splitDecimal splits a decimal number like "-1.234" into its sign, integer
+// and fractional digits. Leading zeros of the integer digits are removed. It
+// returns false for anything else, e.g. numbers with an exponent.
+func splitDecimal(s string) (sign, secs, frac string, ok bool) {
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	secs, frac, _ = strings.Cut(s, ".")
+	if (secs == "" && frac == "") || !isDigits(secs) || !isDigits(frac) {
+		return "", "", "", false
+	}
+
+	secs = strings.TrimLeft(secs, "0")
+	if secs == "" {
+		secs = "0"
+	}
+
+	return sign, secs, frac, true
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// formatValue formats a sample value like Prometheus. Special values are
+// encoded as "NaN", "+Inf" and "-Inf", very small and very large values
+// use an exponent.
+func formatValue(v float64) string {
+	format := byte('f')
+	if abs := math.Abs(v); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+
+	return strconv.FormatFloat(v, format, -1, 64)
+}
+
 func (m *PromqlMarshaler) NewEncoder(w io.Writer) runtime.Encoder {
 	fallbackEncoder := m.fallback.NewEncoder(w)
 	jsonEncoder := json.NewEncoder(w)
@@ -263,6 +414,19 @@ func (m *PromqlMarshaler) Unmarshal(data []byte, v interface{}) error {
 }
 
 func (m *PromqlMarshaler) disassembleInstantQueryResult(q queryResult, iqr *logcache_v1.PromQL_InstantQueryResult) error {
+	iqr.Status = q.Status
+	iqr.ErrorType = q.ErrorType
+	iqr.Error = q.Error
+	iqr.Warnings = q.Warnings
+
+	if q.Status == "error" {
+		return nil
+	}
+
+	if q.Data == nil {
+		return errors.New("missing data in instant query result")
+	}
+
 	switch q.Data.ResultType {
 	case "scalar":
 		r, err := unmarshalScalarResultData(q.Data.Result)
@@ -296,6 +460,17 @@ func (m *PromqlMarshaler) disassembleInstantQueryResult(q queryResult, iqr *logc
 			Matrix: r,
 		}
 
+		return nil
+	case "string":
+		r, err := unmarshalStringResultData(q.Data.Result)
+		if err != nil {
+			return err
+		}
+
+		iqr.Result = &logcache_v1.PromQL_InstantQueryResult_StringValue{
+			StringValue: r,
+		}
+
 		return nil
 	default:
 		return fmt.Errorf("unknown instant query resultType '%s'", q.Data.ResultType)
@@ -303,6 +478,19 @@ func (m *PromqlMarshaler) disassembleInstantQueryResult(q queryResult, iqr *logc
 }
 
 func (m *PromqlMarshaler) disassembleRangeQueryResult(q queryResult, rqr *logcache_v1.PromQL_RangeQueryResult) error {
+	rqr.Status = q.Status
+	rqr.ErrorType = q.ErrorType
+	rqr.Error = q.Error
+	rqr.Warnings = q.Warnings
+
+	if q.Status == "error" {
+		return nil
+	}
+
+	if q.Data == nil {
+		return errors.New("missing data in range query result")
+	}
+
 	switch q.Data.ResultType {
 	case "matrix":
 		r, err := unmarshalMatrixResultData(q.Data.Result)
@@ -322,7 +510,7 @@ func (m *PromqlMarshaler) disassembleRangeQueryResult(q queryResult, rqr *logcac
 
 func unmarshalScalarResultData(data []byte) (*logcache_v1.PromQL_Scalar, error) {
 	var point []interface{}
-	err := json.Unmarshal(data, &point)
+	err := unmarshalJSON(data, &point)
 	if err != nil {
 		return nil, err
 	}
@@ -338,9 +526,41 @@ func unmarshalScalarResultData(data []byte) (*logcache_v1.PromQL_Scalar, error)
 	}, nil
 }
 
+func unmarshalStringResultData(data []byte) (*logcache_v1.PromQL_String, error) {
+	var point []interface{}
+	err := unmarshalJSON(data, &point)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(point) != 2 {
+		return nil, fmt.Errorf("invalid length of string, got %d, expected 2", len(point))
+	}
+
+	n, ok := point[0].(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("invalid type of string timestamp, got %T, expected number", point[0])
+	}
+
+	t, err := parseTimestamp(n)
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := point[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid type of string value, got %T, expected string", point[1])
+	}
+
+	return &logcache_v1.PromQL_String{
+		Time:  t,
+		Value: v,
+	}, nil
+}
+
 func unmarshalVectorResultData(data []byte) (*logcache_v1.PromQL_Vector, error) {
 	var samples []sample
-	err := json.Unmarshal(data, &samples)
+	err := unmarshalJSON(data, &samples)
 	if err != nil {
 		return nil, err
 	}
@@ -367,7 +587,7 @@ func unmarshalVectorResultData(data []byte) (*logcache_v1.PromQL_Vector, error)
 
 func unmarshalMatrixResultData(data []byte) (*logcache_v1.PromQL_Matrix, error) {
 	var values []series
-	err := json.Unmarshal(data, &values)
+	err := unmarshalJSON(data, &values)
 	if err != nil {
 		return nil, err
 	}
@@ -402,11 +622,16 @@ func disassemblePoint(point []interface{}) (string, float64, error) {
 		return "", 0, fmt.Errorf("invalid length of point, got %d, expected 2", len(point))
 	}
 
-	t, ok := point[0].(float64)
+	n, ok := point[0].(json.Number)
 	if !ok {
 		return "", 0, fmt.Errorf("invalid type of point timestamp, got %T, expected number", point[0])
 	}
 
+	t, err := parseTimestamp(n)
+	if err != nil {
+		return "", 0, err
+	}
+
 	v, ok := point[1].(string)
 	if !ok {
 		return "", 0, fmt.Errorf("invalid type of value, got %T, expected string", point[1])
@@ -417,7 +642,15 @@ func disassemblePoint(point []interface{}) (string, float64, error) {
 		return "", 0, fmt.Errorf("failed to parse value: %q", err)
 	}
 
-	return strconv.FormatFloat(t, 'f', 3, 64), decodedValue, nil
+	return t, decodedValue, nil
+}
+
+// unmarshalJSON decodes numbers as json.Number to not lose the precision of
+// timestamps.
+func unmarshalJSON(data []byte, v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	return d.Decode(v)
 }
 
 func (m *PromqlMarshaler) NewDecoder(r io.Reader) runtime.Decoder {
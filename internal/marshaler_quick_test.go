@@ -0,0 +1,279 @@
+package internal_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	marshaler "code.cloudfoundry.org/go-log-cache/v3/internal"
+	"code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPromqlMarshalerRoundTripsInstantQueryResults(t *testing.T) {
+	t.Parallel()
+
+	m := marshaler.NewPromqlMarshaler(&runtime.JSONPb{})
+	f := func(r instantQueryResult) bool {
+		data, err := m.Marshal(r.PromQL_InstantQueryResult)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+
+		var got logcache_v1.PromQL_InstantQueryResult
+		if err := m.Unmarshal(data, &got); err != nil {
+			t.Log(err)
+			return false
+		}
+
+		if !reflect.DeepEqual(normalize(r.PromQL_InstantQueryResult), normalize(&got)) {
+			t.Logf("%s did not round-trip: %v", data, &got)
+			return false
+		}
+
+		return true
+	}
+
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPromqlMarshalerRoundTripsRangeQueryResults(t *testing.T) {
+	t.Parallel()
+
+	m := marshaler.NewPromqlMarshaler(&runtime.JSONPb{})
+	f := func(r rangeQueryResult) bool {
+		data, err := m.Marshal(r.PromQL_RangeQueryResult)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+
+		var got logcache_v1.PromQL_RangeQueryResult
+		if err := m.Unmarshal(data, &got); err != nil {
+			t.Log(err)
+			return false
+		}
+
+		if !reflect.DeepEqual(normalize(r.PromQL_RangeQueryResult), normalize(&got)) {
+			t.Logf("%s did not round-trip: %v", data, &got)
+			return false
+		}
+
+		return true
+	}
+
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPromqlMarshalerEncodesLikePrometheus(t *testing.T) {
+	t.Parallel()
+
+	m := marshaler.NewPromqlMarshaler(&runtime.JSONPb{})
+	for _, tc := range []struct {
+		time  string
+		value float64
+		want  string
+	}{
+		{"1", math.NaN(), `[1,"NaN"]`},
+		{"1.000", math.Inf(1), `[1,"+Inf"]`},
+		{"1.500", math.Inf(-1), `[1.500,"-Inf"]`},
+		{"1.5", 1, `[1.500,"1"]`},
+		{"1.234", math.Copysign(0, -1), `[1.234,"-0"]`},
+		{"1700000000.123456789", 1e-7, `[1700000000.123456789,"1e-07"]`},
+		{"0.001", 1e21, `[0.001,"1e+21"]`},
+		{"-1.250", 0.1, `[-1.250,"0.1"]`},
+	} {
+		data, err := m.Marshal(&logcache_v1.PromQL_InstantQueryResult{
+			Result: &logcache_v1.PromQL_InstantQueryResult_Scalar{
+				Scalar: &logcache_v1.PromQL_Scalar{Time: tc.time, Value: tc.value},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var result struct {
+			Data struct {
+				Result json.RawMessage `json:"result"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			t.Fatal(err)
+		}
+
+		if string(result.Data.Result) != tc.want {
+			t.Errorf("expected %s to equal %s", result.Data.Result, tc.want)
+		}
+	}
+}
+
+type instantQueryResult struct {
+	*logcache_v1.PromQL_InstantQueryResult
+}
+
+func (instantQueryResult) Generate(r *rand.Rand, size int) reflect.Value {
+	v := &logcache_v1.PromQL_InstantQueryResult{
+		Warnings: randomWarnings(r),
+	}
+
+	switch r.Intn(4) {
+	case 0:
+		v.Result = &logcache_v1.PromQL_InstantQueryResult_Scalar{
+			Scalar: &logcache_v1.PromQL_Scalar{
+				Time:  randomTimestamp(r),
+				Value: randomValue(r),
+			},
+		}
+	case 1:
+		var samples []*logcache_v1.PromQL_Sample
+		for range r.Intn(size + 1) {
+			samples = append(samples, &logcache_v1.PromQL_Sample{
+				Metric: randomMetric(r),
+				Point:  randomPoint(r),
+			})
+		}
+
+		v.Result = &logcache_v1.PromQL_InstantQueryResult_Vector{
+			Vector: &logcache_v1.PromQL_Vector{Samples: samples},
+		}
+	case 2:
+		v.Result = &logcache_v1.PromQL_InstantQueryResult_Matrix{
+			Matrix: randomMatrix(r, size),
+		}
+	case 3:
+		v.Result = &logcache_v1.PromQL_InstantQueryResult_StringValue{
+			StringValue: &logcache_v1.PromQL_String{
+				Time:  randomTimestamp(r),
+				Value: randomString(r),
+			},
+		}
+	}
+
+	return reflect.ValueOf(instantQueryResult{v})
+}
+
+type rangeQueryResult struct {
+	*logcache_v1.PromQL_RangeQueryResult
+}
+
+func (rangeQueryResult) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(rangeQueryResult{&logcache_v1.PromQL_RangeQueryResult{
+		Result: &logcache_v1.PromQL_RangeQueryResult_Matrix{
+			Matrix: randomMatrix(r, size),
+		},
+		Warnings: randomWarnings(r),
+	}})
+}
+
+func randomMatrix(r *rand.Rand, size int) *logcache_v1.PromQL_Matrix {
+	var series []*logcache_v1.PromQL_Series
+	for range r.Intn(size + 1) {
+		// Prometheus never returns series without points.
+		points := []*logcache_v1.PromQL_Point{randomPoint(r)}
+		for range r.Intn(size + 1) {
+			points = append(points, randomPoint(r))
+		}
+
+		series = append(series, &logcache_v1.PromQL_Series{
+			Metric: randomMetric(r),
+			Points: points,
+		})
+	}
+
+	return &logcache_v1.PromQL_Matrix{Series: series}
+}
+
+func randomPoint(r *rand.Rand) *logcache_v1.PromQL_Point {
+	return &logcache_v1.PromQL_Point{
+		Time:  randomTimestamp(r),
+		Value: randomValue(r),
+	}
+}
+
+// randomTimestamp returns a timestamp in the format the marshaler decodes
+// to: seconds with at least three decimal places.
+func randomTimestamp(r *rand.Rand) string {
+	t := fmt.Sprintf("%d.%03d", r.Int63n(1<<34)-1<<30, r.Intn(1000))
+	if r.Intn(3) == 0 {
+		// Sub-millisecond precision.
+		t += strconv.Itoa(r.Intn(1000)) + strconv.Itoa(1+r.Intn(9))
+	}
+
+	return t
+}
+
+func randomValue(r *rand.Rand) float64 {
+	switch r.Intn(8) {
+	case 0:
+		return math.NaN()
+	case 1:
+		return math.Inf(1)
+	case 2:
+		return math.Inf(-1)
+	case 3:
+		return math.Copysign(0, -1)
+	case 4:
+		return r.NormFloat64()
+	default:
+		return math.Float64frombits(r.Uint64())
+	}
+}
+
+func randomMetric(r *rand.Rand) map[string]string {
+	m := make(map[string]string)
+	for range r.Intn(4) {
+		m[randomString(r)] = randomString(r)
+	}
+
+	return m
+}
+
+func randomWarnings(r *rand.Rand) []string {
+	var w []string
+	for range r.Intn(3) {
+		w = append(w, randomString(r))
+	}
+
+	return w
+}
+
+func randomString(r *rand.Rand) string {
+	var b strings.Builder
+	for range 1 + r.Intn(10) {
+		b.WriteRune(' ' + r.Int31n(95))
+	}
+
+	return b.String()
+}
+
+// normalize returns the text format of the result. It sorts maps, prints
+// any NaN as "nan" and distinguishes 0 from -0.
+func normalize(m proto.Message) string {
+	m = proto.Clone(m)
+
+	switch v := m.(type) {
+	case *logcache_v1.PromQL_InstantQueryResult:
+		if v.Status == "" {
+			v.Status = "success"
+		}
+	case *logcache_v1.PromQL_RangeQueryResult:
+		if v.Status == "" {
+			v.Status = "success"
+		}
+	}
+
+	return prototext.Format(m)
+}
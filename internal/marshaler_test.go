@@ -428,6 +428,45 @@ var _ = Describe("PromqlMarshaler", func() {
 			}`))
 		})
 
+		It("handles a string instant query result", func() {
+			marshaler := marshaler.NewPromqlMarshaler(&mockMarshaler{})
+
+			result, err := marshaler.Marshal(&logcache_v1.PromQL_InstantQueryResult{
+				Result: &logcache_v1.PromQL_InstantQueryResult_StringValue{
+					StringValue: &logcache_v1.PromQL_String{
+						Time:  "1.234",
+						Value: "some-string",
+					},
+				},
+				Warnings: []string{"some-warning"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(MatchJSON(`{
+				"status": "success",
+				"data": {
+					"resultType": "string",
+					"result": [1.234, "some-string"]
+				},
+				"warnings": ["some-warning"]
+			}`))
+		})
+
+		It("handles an error result", func() {
+			marshaler := marshaler.NewPromqlMarshaler(&mockMarshaler{})
+
+			result, err := marshaler.Marshal(&logcache_v1.PromQL_RangeQueryResult{
+				Status:    "error",
+				ErrorType: "bad_data",
+				Error:     "some-error",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(MatchJSON(`{
+				"status": "error",
+				"errorType": "bad_data",
+				"error": "some-error"
+			}`))
+		})
+
 		It("reports errors for invalid timestamps", func() {
 			marshaler := marshaler.NewPromqlMarshaler(&mockMarshaler{})
 
@@ -526,6 +565,7 @@ var _ = Describe("PromqlMarshaler", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(&result).To(Equal(&logcache_v1.PromQL_InstantQueryResult{
+				Status: "success",
 				Result: &logcache_v1.PromQL_InstantQueryResult_Scalar{
 					Scalar: &logcache_v1.PromQL_Scalar{
 						Time:  "1.777",
@@ -564,6 +604,7 @@ var _ = Describe("PromqlMarshaler", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(&result).To(Equal(&logcache_v1.PromQL_InstantQueryResult{
+				Status: "success",
 				Result: &logcache_v1.PromQL_InstantQueryResult_Vector{
 					Vector: &logcache_v1.PromQL_Vector{
 						Samples: []*logcache_v1.PromQL_Sample{
@@ -628,6 +669,7 @@ var _ = Describe("PromqlMarshaler", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(&result).To(Equal(&logcache_v1.PromQL_InstantQueryResult{
+				Status: "success",
 				Result: &logcache_v1.PromQL_InstantQueryResult_Matrix{
 					Matrix: &logcache_v1.PromQL_Matrix{
 						Series: []*logcache_v1.PromQL_Series{
@@ -704,6 +746,7 @@ var _ = Describe("PromqlMarshaler", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(&result).To(Equal(&logcache_v1.PromQL_RangeQueryResult{
+				Status: "success",
 				Result: &logcache_v1.PromQL_RangeQueryResult_Matrix{
 					Matrix: &logcache_v1.PromQL_Matrix{
 						Series: []*logcache_v1.PromQL_Series{
@@ -745,6 +788,43 @@ var _ = Describe("PromqlMarshaler", func() {
 			}))
 		})
 
+		It("handles a string instant query result", func() {
+			marshaler := marshaler.NewPromqlMarshaler(&mockMarshaler{})
+
+			var result logcache_v1.PromQL_InstantQueryResult
+			err := marshaler.Unmarshal([]byte(`{
+				"status": "success",
+				"data": {
+					"resultType": "string",
+					"result": [1.777, "some-string"]
+				},
+				"warnings": ["some-warning"]
+			}`), &result)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(result.GetStatus()).To(Equal("success"))
+			Expect(result.GetWarnings()).To(Equal([]string{"some-warning"}))
+			Expect(result.GetStringValue().GetTime()).To(Equal("1.777"))
+			Expect(result.GetStringValue().GetValue()).To(Equal("some-string"))
+		})
+
+		It("handles an error result", func() {
+			marshaler := marshaler.NewPromqlMarshaler(&mockMarshaler{})
+
+			var result logcache_v1.PromQL_InstantQueryResult
+			err := marshaler.Unmarshal([]byte(`{
+				"status": "error",
+				"errorType": "bad_data",
+				"error": "some-error"
+			}`), &result)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(result.GetStatus()).To(Equal("error"))
+			Expect(result.GetErrorType()).To(Equal("bad_data"))
+			Expect(result.GetError()).To(Equal("some-error"))
+			Expect(result.GetResult()).To(BeNil())
+		})
+
 		It("falls back to the fallback marshaler", func() {
 			marshaler := marshaler.NewPromqlMarshaler(&mockMarshaler{})
 
@@ -781,6 +861,7 @@ var _ = Describe("PromqlMarshaler", func() {
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(&result).To(Equal(&logcache_v1.PromQL_InstantQueryResult{
+				Status: "success",
 				Result: &logcache_v1.PromQL_InstantQueryResult_Scalar{
 					Scalar: &logcache_v1.PromQL_Scalar{
 						Time:  "1.123",
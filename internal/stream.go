@@ -0,0 +1,243 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
+)
+
+// SeriesDecoder decodes the series of a range query result one at a time.
+// Unlike Unmarshal, only a single series is held in memory at any time.
+type SeriesDecoder struct {
+	d *json.Decoder
+
+	started  bool
+	inResult bool
+	err      error
+
+	status     string
+	resultType string
+	errorType  string
+	message    string
+	warnings   []string
+}
+
+// NewSeriesDecoder returns a SeriesDecoder that reads a range query result
+// in the format of the Prometheus HTTP API from the reader.
+func NewSeriesDecoder(r io.Reader) *SeriesDecoder {
+	d := json.NewDecoder(r)
+	d.UseNumber()
+
+	return &SeriesDecoder{d: d}
+}
+
+// Next returns the next series of the result. It returns io.EOF once the
+// whole result has been read.
+func (s *SeriesDecoder) Next() (*logcache_v1.PromQL_Series, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	series, err := s.next()
+	if err != nil {
+		s.err = err
+	}
+
+	return series, err
+}
+
+func (s *SeriesDecoder) next() (*logcache_v1.PromQL_Series, error) {
+	if !s.started {
+		s.started = true
+		if err := s.expectDelim('{'); err != nil {
+			return nil, err
+		}
+
+		if err := s.topLevelKeys(); err != nil {
+			return nil, err
+		}
+	}
+
+	if !s.inResult {
+		return nil, io.EOF
+	}
+
+	if s.d.More() {
+		return s.decodeSeries()
+	}
+
+	// The result is done, so read whatever follows it.
+	s.inResult = false
+	if err := s.expectDelim(']'); err != nil {
+		return nil, err
+	}
+
+	if err := s.dataKeys(); err != nil {
+		return nil, err
+	}
+
+	if err := s.topLevelKeys(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+// Status returns the 'status' field of the result. Like ErrorType, Error
+// and Warnings, it is only complete once Next returned io.EOF.
+func (s *SeriesDecoder) Status() string {
+	return s.status
+}
+
+// ErrorType returns the 'errorType' field of the result.
+func (s *SeriesDecoder) ErrorType() string {
+	return s.errorType
+}
+
+// Error returns the 'error' field of the result.
+func (s *SeriesDecoder) Error() string {
+	return s.message
+}
+
+// Warnings returns the 'warnings' field of the result.
+func (s *SeriesDecoder) Warnings() []string {
+	return s.warnings
+}
+
+// topLevelKeys reads the keys of the result object until it ends or the
+// result array starts.
+func (s *SeriesDecoder) topLevelKeys() error {
+	for s.d.More() {
+		key, err := s.key()
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case "status":
+			err = s.d.Decode(&s.status)
+		case "errorType":
+			err = s.d.Decode(&s.errorType)
+		case "error":
+			err = s.d.Decode(&s.message)
+		case "warnings":
+			err = s.d.Decode(&s.warnings)
+		case "data":
+			if err := s.expectDelim('{'); err != nil {
+				return err
+			}
+
+			err = s.dataKeys()
+			if s.inResult {
+				return err
+			}
+		default:
+			err = s.skip()
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.expectDelim('}')
+}
+
+// dataKeys reads the keys of the data object until it ends or the result
+// array starts.
+func (s *SeriesDecoder) dataKeys() error {
+	for s.d.More() {
+		key, err := s.key()
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case "resultType":
+			if err := s.d.Decode(&s.resultType); err != nil {
+				return err
+			}
+
+			if s.resultType != "matrix" {
+				return fmt.Errorf("unknown range query resultType '%s'", s.resultType)
+			}
+		case "result":
+			if err := s.expectDelim('['); err != nil {
+				return err
+			}
+
+			s.inResult = true
+			return nil
+		default:
+			if err := s.skip(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.expectDelim('}')
+}
+
+func (s *SeriesDecoder) decodeSeries() (*logcache_v1.PromQL_Series, error) {
+	var v series
+	if err := s.d.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	points := make([]*logcache_v1.PromQL_Point, 0, len(v.Values))
+	for _, point := range v.Values {
+		time, value, err := disassemblePoint(point)
+		if err != nil {
+			return nil, err
+		}
+
+		points = append(points, &logcache_v1.PromQL_Point{
+			Time:  time,
+			Value: value,
+		})
+	}
+
+	return &logcache_v1.PromQL_Series{
+		Metric: v.Metric,
+		Points: points,
+	}, nil
+}
+
+func (s *SeriesDecoder) key() (string, error) {
+	t, err := s.d.Token()
+	if err != nil {
+		return "", err
+	}
+
+	key, ok := t.(string)
+	if !ok {
+		return "", fmt.Errorf("expected an object key, got %v", t)
+	}
+
+	return key, nil
+}
+
+func (s *SeriesDecoder) expectDelim(delim json.Delim) error {
+	t, err := s.d.Token()
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if t != delim {
+		return fmt.Errorf("expected %s, got %v", delim, t)
+	}
+
+	return nil
+}
+
+// skip skips a value that is not needed.
+func (s *SeriesDecoder) skip() error {
+	var v json.RawMessage
+	return s.d.Decode(&v)
+}
@@ -0,0 +1,111 @@
+package internal_test
+
+import (
+	"io"
+	"strings"
+
+	marshaler "code.cloudfoundry.org/go-log-cache/v3/internal"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SeriesDecoder", func() {
+	It("decodes the series one at a time", func() {
+		d := marshaler.NewSeriesDecoder(strings.NewReader(`{
+			"status": "success",
+			"data": {
+				"resultType": "matrix",
+				"result": [
+					{"metric": {"a": "1"}, "values": [[1, "1.5"], [2.5, "NaN"]]},
+					{"metric": {"a": "2"}, "values": [[3, "+Inf"]]}
+				]
+			},
+			"warnings": ["some-warning"]
+		}`))
+
+		s, err := d.Next()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s.GetMetric()).To(Equal(map[string]string{"a": "1"}))
+		Expect(s.GetPoints()).To(HaveLen(2))
+		Expect(s.GetPoints()[1].GetTime()).To(Equal("2.500"))
+
+		s, err = d.Next()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s.GetMetric()).To(Equal(map[string]string{"a": "2"}))
+
+		_, err = d.Next()
+		Expect(err).To(Equal(io.EOF))
+		Expect(d.Status()).To(Equal("success"))
+		Expect(d.Warnings()).To(Equal([]string{"some-warning"}))
+	})
+
+	It("reports errors", func() {
+		d := marshaler.NewSeriesDecoder(strings.NewReader(`{
+			"status": "error",
+			"errorType": "bad_data",
+			"error": "some-error"
+		}`))
+
+		_, err := d.Next()
+		Expect(err).To(Equal(io.EOF))
+		Expect(d.Status()).To(Equal("error"))
+		Expect(d.ErrorType()).To(Equal("bad_data"))
+		Expect(d.Error()).To(Equal("some-error"))
+	})
+
+	It("returns an error for other result types", func() {
+		d := marshaler.NewSeriesDecoder(strings.NewReader(`{
+			"status": "success",
+			"data": {"resultType": "vector", "result": []}
+		}`))
+
+		_, err := d.Next()
+		Expect(err).To(HaveOccurred())
+		Expect(err).ToNot(Equal(io.EOF))
+	})
+
+	It("returns an error for truncated results", func() {
+		d := marshaler.NewSeriesDecoder(strings.NewReader(`{
+			"status": "success",
+			"data": {"resultType": "matrix", "result": [
+				{"metric": {}, "values": [[1, "1"]]}
+		`))
+
+		_, err := d.Next()
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = d.Next()
+		Expect(err).To(HaveOccurred())
+		Expect(err).ToNot(Equal(io.EOF))
+	})
+
+	It("does not read the whole result before yielding a series", func() {
+		d := marshaler.NewSeriesDecoder(io.MultiReader(
+			strings.NewReader(`{"status":"success","data":{"resultType":"matrix","result":[`),
+			&endlessSeries{},
+		))
+
+		for range 1000 {
+			s, err := d.Next()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.GetPoints()).To(HaveLen(1))
+		}
+	})
+})
+
+// endlessSeries is a reader that never ends, so it cannot be read into
+// memory.
+type endlessSeries struct {
+	buf []byte
+}
+
+func (s *endlessSeries) Read(p []byte) (int, error) {
+	if len(s.buf) == 0 {
+		s.buf = []byte(`{"metric":{"a":"b"},"values":[[1,"1"]]},`)
+	}
+
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+	return n, nil
+}
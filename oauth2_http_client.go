@@ -2,17 +2,23 @@ package client
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
 	"sync"
 	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/sync/singleflight"
 )
 
 // Oauth2HTTPClient sets the "Authorization" header of any outgoing request.
-// It gets a JWT from the configured Oauth2 server. It only gets a new JWT
-// when a request comes back with a 401.
+// It gets a JWT from the configured Oauth2 server. It gets a new JWT shortly
+// before the current one expires, or when a request comes back with a 401
+// or 403. It is safe for concurrent use.
 type Oauth2HTTPClient struct {
 	c            HTTPClient
 	oauth2Addr   string
@@ -22,16 +28,28 @@ type Oauth2HTTPClient struct {
 	username     string
 	userPassword string
 
-	mu    sync.Mutex
-	token string
+	tokenPath       string
+	refreshSkew     time.Duration
+	useRefreshToken bool
+	jwtAssertion    func() (string, error)
+	tokenSource     Oauth2TokenSource
+
+	fetches singleflight.Group
+
+	mu           sync.Mutex
+	token        string
+	expiry       time.Time
+	refreshToken string
 }
 
 // NewOauth2HTTPClient creates a new Oauth2HTTPClient.
 func NewOauth2HTTPClient(oauth2Addr, client, clientSecret string, opts ...Oauth2Option) *Oauth2HTTPClient {
-	c := &Oauth2HTTPClient{
+	c := &Oauth2HTTPClient{ //nolint:gosec
 		oauth2Addr:   oauth2Addr,
 		client:       client,
 		clientSecret: clientSecret,
+		tokenPath:    "/oauth/token",
+		refreshSkew:  30 * time.Second,
 
 		c: &http.Client{
 			Timeout: 5 * time.Second,
@@ -66,6 +84,85 @@ func WithOauth2HTTPUser(username, password string) Oauth2Option {
 	})
 }
 
+// WithOauth2RefreshSkew sets how long before its expiry a token is replaced.
+// It defaults to 30 seconds.
+func WithOauth2RefreshSkew(skew time.Duration) Oauth2Option {
+	return oauth2HTTPClientOptionFunc(func(c *Oauth2HTTPClient) {
+		c.refreshSkew = skew
+	})
+}
+
+// WithOauth2TokenPath sets the path of the token endpoint of the Oauth2
+// server. It defaults to "/oauth/token".
+func WithOauth2TokenPath(path string) Oauth2Option {
+	return oauth2HTTPClientOptionFunc(func(c *Oauth2HTTPClient) {
+		c.tokenPath = path
+	})
+}
+
+// WithOauth2RefreshToken gets tokens via the refresh_token grant with the
+// given refresh token, e.g. the one stored by the cf CLI. If the refresh
+// token is rejected, the password grant is used if WithOauth2HTTPUser is
+// given. Otherwise the error is returned.
+func WithOauth2RefreshToken(refreshToken string) Oauth2Option {
+	return oauth2HTTPClientOptionFunc(func(c *Oauth2HTTPClient) {
+		c.refreshToken = refreshToken
+		c.useRefreshToken = true
+	})
+}
+
+// WithOauth2Token sets the token to use until it expires or is rejected,
+// e.g. a token stored by the cf CLI. Its refresh token is used to get the
+// next token.
+func WithOauth2Token(t Oauth2Token) Oauth2Option {
+	return oauth2HTTPClientOptionFunc(func(c *Oauth2HTTPClient) {
+		c.token = t.authorization()
+		c.expiry = t.Expiry
+		if t.RefreshToken != "" {
+			c.refreshToken = t.RefreshToken
+			c.useRefreshToken = true
+		}
+	})
+}
+
+// WithOauth2JWTBearer gets tokens via the JWT bearer grant (RFC 7523). The
+// given function is invoked for every token request to get the assertion.
+func WithOauth2JWTBearer(assertion func() (string, error)) Oauth2Option {
+	return oauth2HTTPClientOptionFunc(func(c *Oauth2HTTPClient) {
+		c.jwtAssertion = assertion
+	})
+}
+
+// WithOauth2TokenSource gets tokens from the given Oauth2TokenSource instead
+// of the Oauth2 server.
+func WithOauth2TokenSource(ts Oauth2TokenSource) Oauth2Option {
+	return oauth2HTTPClientOptionFunc(func(c *Oauth2HTTPClient) {
+		c.tokenSource = ts
+	})
+}
+
+// WithOauth2XTokenSource gets tokens from the given TokenSource of
+// golang.org/x/oauth2 instead of the Oauth2 server.
+func WithOauth2XTokenSource(ts oauth2.TokenSource) Oauth2Option {
+	return WithOauth2TokenSource(Oauth2TokenSourceFunc(func() (*Oauth2Token, error) {
+		t, err := ts.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		if t == nil {
+			return nil, nil
+		}
+
+		return &Oauth2Token{
+			AccessToken:  t.AccessToken,
+			TokenType:    t.Type(),
+			RefreshToken: t.RefreshToken,
+			Expiry:       t.Expiry,
+		}, nil
+	}))
+}
+
 // oauth2HTTPClientOptionFunc enables a function to be a
 // Oauth2Option.
 type oauth2HTTPClientOptionFunc func(c *Oauth2HTTPClient)
@@ -80,6 +177,10 @@ func (f oauth2HTTPClientOptionFunc) configure(c *Oauth2HTTPClient) {
 // out the Oauth2 server and get a new one. The given error CAN be from the
 // request to the Oauth2 server.
 //
+// If the request comes back with a 401, Do gets a new token and retries the
+// request once. Requests with a body are only retried if the body can be
+// reset via GetBody (see http.NewRequest).
+//
 // Do modifies the given Request. It is invalid to use the same Request
 // instance on multiple go-routines.
 func (c *Oauth2HTTPClient) Do(req *http.Request) (*http.Response, error) {
@@ -88,6 +189,30 @@ func (c *Oauth2HTTPClient) Do(req *http.Request) (*http.Response, error) {
 		return c.c.Do(req)
 	}
 
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		req.Body = body
+	}
+
+	io.Copy(io.Discard, resp.Body) //nolint:errcheck
+	resp.Body.Close()              //nolint:errcheck
+
+	return c.do(req)
+}
+
+func (c *Oauth2HTTPClient) do(req *http.Request) (*http.Response, error) {
 	token, err := c.getToken()
 	if err != nil {
 		return nil, err
@@ -100,58 +225,155 @@ func (c *Oauth2HTTPClient) Do(req *http.Request) (*http.Response, error) {
 		return nil, err
 	}
 
-	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-		c.token = ""
-		return resp, nil
+	// Only a rejected token is replaced, so that an unrelated outage does not
+	// result in a token request for every request.
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		c.invalidateToken(token)
 	}
 
 	return resp, nil
 }
 
-func (c *Oauth2HTTPClient) getToken() (string, error) {
-	if c.username == "" {
-		return c.getClientToken()
+// Oauth2Token is a token as returned by an Oauth2 server. It has the same
+// fields as the Token of golang.org/x/oauth2.
+type Oauth2Token struct {
+	AccessToken  string
+	TokenType    string
+	RefreshToken string
+
+	// Expiry is the time the token expires at. A zero Expiry means that the
+	// token does not expire.
+	Expiry time.Time
+}
+
+// authorization returns the value for the Authorization header.
+func (t *Oauth2Token) authorization() string {
+	if t.TokenType == "" {
+		return "Bearer " + t.AccessToken
 	}
-	return c.getUserToken()
+
+	return t.TokenType + " " + t.AccessToken
 }
 
-func (c *Oauth2HTTPClient) getClientToken() (string, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// Oauth2TokenSource supplies tokens. Its Token method returns an
+// Oauth2Token rather than the *oauth2.Token of golang.org/x/oauth2, so a
+// TokenSource of that package does not satisfy it. Use
+// WithOauth2XTokenSource for those instead.
+type Oauth2TokenSource interface {
+	Token() (*Oauth2Token, error)
+}
 
-	if c.token != "" {
-		return c.token, nil
+// Oauth2TokenSourceFunc enables a function to be an Oauth2TokenSource.
+type Oauth2TokenSourceFunc func() (*Oauth2Token, error)
+
+// Token implements Oauth2TokenSource.
+func (f Oauth2TokenSourceFunc) Token() (*Oauth2Token, error) {
+	return f()
+}
+
+func (c *Oauth2HTTPClient) getToken() (string, error) {
+	if token, ok := c.currentToken(); ok {
+		return token, nil
 	}
 
-	v := make(url.Values)
-	v.Set("client_id", c.client)
-	v.Set("grant_type", "client_credentials")
+	// Concurrent callers share a single request to the Oauth2 server.
+	token, err, _ := c.fetches.Do("token", func() (interface{}, error) {
+		if token, ok := c.currentToken(); ok {
+			// Another request completed in the meantime.
+			return token, nil
+		}
 
-	req, err := http.NewRequest( //nolint:gosec
-		"POST",
-		c.oauth2Addr,
-		strings.NewReader(v.Encode()),
-	)
+		t, err := c.fetchToken()
+		if err != nil {
+			return "", err
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.token = t.authorization()
+		c.expiry = t.Expiry
+		if t.RefreshToken != "" {
+			c.refreshToken = t.RefreshToken
+		}
+
+		return c.token, nil
+	})
 	if err != nil {
 		return "", err
 	}
-	req.URL.Path = "/oauth/token"
 
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return token.(string), nil
+}
 
-	req.URL.User = url.UserPassword(c.client, c.clientSecret)
+// currentToken returns the token unless it has to be replaced.
+func (c *Oauth2HTTPClient) currentToken() (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	return c.doTokenRequest(req)
+	valid := c.token != "" && (c.expiry.IsZero() || time.Now().Add(c.refreshSkew).Before(c.expiry))
+	return c.token, valid
 }
 
-func (c *Oauth2HTTPClient) getUserToken() (string, error) {
+// invalidateToken forces the given token to be replaced. It does nothing if
+// the token has been replaced already.
+func (c *Oauth2HTTPClient) invalidateToken(token string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.token != "" {
-		return c.token, nil
+	if c.token == token {
+		c.token = ""
+	}
+}
+
+func (c *Oauth2HTTPClient) fetchToken() (*Oauth2Token, error) {
+	if c.tokenSource != nil {
+		t, err := c.tokenSource.Token()
+		if err == nil && t == nil {
+			err = errors.New("token source returned no token")
+		}
+		return t, err
+	}
+
+	if c.jwtAssertion != nil {
+		return c.getJWTBearerToken()
+	}
+
+	c.mu.Lock()
+	refreshToken := c.refreshToken
+	c.mu.Unlock()
+
+	if refreshToken != "" && (c.useRefreshToken || c.username != "") {
+		t, err := c.getRefreshedToken(refreshToken)
+		if err == nil || c.username == "" {
+			return t, err
+		}
+
+		// The refresh token might have expired or been revoked, so fall back
+		// to the password.
+		c.mu.Lock()
+		if c.refreshToken == refreshToken {
+			c.refreshToken = ""
+		}
+		c.mu.Unlock()
+	}
+
+	if c.username == "" {
+		return c.getClientToken()
 	}
 
+	return c.getUserToken()
+}
+
+func (c *Oauth2HTTPClient) getClientToken() (*Oauth2Token, error) {
+	v := make(url.Values)
+	v.Set("client_id", c.client)
+	v.Set("grant_type", "client_credentials")
+
+	return c.doTokenRequest(v, true)
+}
+
+func (c *Oauth2HTTPClient) getUserToken() (*Oauth2Token, error) {
 	v := make(url.Values)
 	v.Set("client_id", c.client)
 	v.Set("client_secret", c.clientSecret)
@@ -159,42 +381,84 @@ func (c *Oauth2HTTPClient) getUserToken() (string, error) {
 	v.Set("username", c.username)
 	v.Set("password", c.userPassword)
 
+	return c.doTokenRequest(v, false)
+}
+
+func (c *Oauth2HTTPClient) getRefreshedToken(refreshToken string) (*Oauth2Token, error) {
+	v := make(url.Values)
+	v.Set("client_id", c.client)
+	v.Set("client_secret", c.clientSecret)
+	v.Set("grant_type", "refresh_token")
+	v.Set("refresh_token", refreshToken)
+
+	return c.doTokenRequest(v, false)
+}
+
+func (c *Oauth2HTTPClient) getJWTBearerToken() (*Oauth2Token, error) {
+	assertion, err := c.jwtAssertion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get JWT assertion: %s", err)
+	}
+
+	v := make(url.Values)
+	v.Set("client_id", c.client)
+	v.Set("client_secret", c.clientSecret)
+	v.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	v.Set("assertion", assertion)
+
+	return c.doTokenRequest(v, false)
+}
+
+// doTokenRequest sends the given parameters to the token endpoint. With
+// basicAuth the client credentials are sent via the Authorization header.
+func (c *Oauth2HTTPClient) doTokenRequest(v url.Values, basicAuth bool) (*Oauth2Token, error) {
 	req, err := http.NewRequest( //nolint:gosec
 		"POST",
 		c.oauth2Addr,
 		strings.NewReader(v.Encode()),
 	)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	req.URL.Path = "/oauth/token"
+	req.URL.Path = c.tokenPath
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	return c.doTokenRequest(req)
-}
+	if basicAuth {
+		req.URL.User = url.UserPassword(c.client, c.clientSecret)
+	}
 
-func (c *Oauth2HTTPClient) doTokenRequest(req *http.Request) (string, error) {
 	resp, err := c.c.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code from Oauth2 server %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code from Oauth2 server %d", resp.StatusCode)
 	}
 
 	token := struct {
-		TokenType   string `json:"token_type"`
-		AccessToken string `json:"access_token"` //nolint:gosec
+		TokenType    string `json:"token_type"`
+		AccessToken  string `json:"access_token"`  //nolint:gosec
+		RefreshToken string `json:"refresh_token"` //nolint:gosec
+		ExpiresIn    int64  `json:"expires_in"`
 	}{}
 
 	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
-		return "", fmt.Errorf("failed to unmarshal response from Oauth2 server: %s", err)
+		return nil, fmt.Errorf("failed to unmarshal response from Oauth2 server: %s", err)
+	}
+
+	t := &Oauth2Token{
+		AccessToken:  token.AccessToken,
+		TokenType:    token.TokenType,
+		RefreshToken: token.RefreshToken,
+	}
+
+	if token.ExpiresIn > 0 {
+		t.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
 	}
 
-	c.token = fmt.Sprintf("%s %s", token.TokenType, token.AccessToken)
-	return c.token, nil
+	return t, nil
 }
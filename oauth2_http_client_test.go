@@ -3,13 +3,19 @@ package client_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	client "code.cloudfoundry.org/go-log-cache/v3"
+	"golang.org/x/oauth2"
 )
 
 var _ client.HTTPClient = &client.Oauth2HTTPClient{}
@@ -257,13 +263,19 @@ func TestOauth2HTTPClientReusesToken(t *testing.T) {
 	}
 }
 
-func TestOauth2HTTPClientClearsTokenOnNonSuccessfulStatusCode(t *testing.T) {
+func TestOauth2HTTPClientOnlyClearsTokenOnUnauthorizedOrForbidden(t *testing.T) {
 	t.Parallel()
 
 	stubClient := newStubHTTPClient()
 
-	stubClient.resps = append(stubClient.resps, tokenResp(), &http.Response{StatusCode: 404}, &http.Response{StatusCode: 199})
-	stubClient.errs = append(stubClient.errs, nil, nil, nil)
+	stubClient.resps = append(stubClient.resps,
+		tokenResp(),
+		&http.Response{StatusCode: 404, Body: io.NopCloser(&bytes.Buffer{})},
+		&http.Response{StatusCode: 500, Body: io.NopCloser(&bytes.Buffer{})},
+		&http.Response{StatusCode: 199, Body: io.NopCloser(&bytes.Buffer{})},
+		&http.Response{StatusCode: 403, Body: io.NopCloser(&bytes.Buffer{})},
+	)
+	stubClient.errs = append(stubClient.errs, nil, nil, nil, nil, nil)
 
 	c := client.NewOauth2HTTPClient(
 		"http://oauth2.something.com",
@@ -271,30 +283,34 @@ func TestOauth2HTTPClientClearsTokenOnNonSuccessfulStatusCode(t *testing.T) {
 		"my-password",
 		client.WithOauth2HTTPClient(stubClient),
 	)
-	req, err := http.NewRequest("GET", "http://some-target.com", nil)
-	if err != nil {
-		t.Fatal(err)
+
+	for i := 0; i < 4; i++ {
+		req, err := http.NewRequest("GET", "http://some-target.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := c.Do(req); err != nil {
+			t.Fatal(err)
+		}
 	}
 
-	_, err = c.Do(req)
-	if err != nil {
-		t.Fatal(err)
+	if len(stubClient.reqs) != 5 {
+		t.Fatalf("expected to only get the token once: %d", len(stubClient.reqs))
 	}
 
-	// Returns 401 and clears existing auth token
-	_, err = c.Do(req)
+	// Returns 403 and clears existing auth token
+	req, err := http.NewRequest("GET", "http://some-target.com", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	// Returns 199 and clears existing auth token
-	_, err = c.Do(req)
-	if err != nil {
+	if _, err := c.Do(req); err != nil {
 		t.Fatal(err)
 	}
 
-	if len(stubClient.reqs) != 4 {
-		t.Fatalf("expected to only get the token again: %d", len(stubClient.reqs))
+	if len(stubClient.reqs) != 7 {
+		t.Fatalf("expected to get the token again: %d", len(stubClient.reqs))
 	}
 }
 
@@ -370,6 +386,366 @@ func TestOauth2HTTPClientSurvivesRaceDetector(t *testing.T) {
 	}
 }
 
+func TestOauth2HTTPClientRefreshesTokenBeforeExpiry(t *testing.T) {
+	t.Parallel()
+
+	stubClient := newStubHTTPClient()
+	stubClient.resps = append(stubClient.resps,
+		tokenRespWith(oauth2Resp{TokenType: "bearer", AccessToken: "expiring-token", ExpiresIn: 10}), //nolint:gosec
+		&http.Response{StatusCode: 200, Body: io.NopCloser(&bytes.Buffer{})},
+		tokenRespWith(oauth2Resp{TokenType: "bearer", AccessToken: "some-token", ExpiresIn: 3600}),
+	)
+	stubClient.errs = append(stubClient.errs, nil, nil, nil)
+
+	c := client.NewOauth2HTTPClient(
+		"http://oauth2.something.com",
+		"my-user",
+		"my-password",
+		client.WithOauth2HTTPClient(stubClient),
+	)
+
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequest("GET", "http://some-target.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := c.Do(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The first token expires within the refresh skew, the second one does
+	// not.
+	if len(stubClient.reqs) != 5 {
+		t.Fatalf("expected to get the token twice: %d", len(stubClient.reqs))
+	}
+
+	if stubClient.reqs[4].Header.Get("Authorization") != "bearer some-token" {
+		t.Fatalf("expected the refreshed token to be used: %s", stubClient.reqs[4].Header.Get("Authorization"))
+	}
+}
+
+func TestOauth2HTTPClientUsesRefreshTokenForPasswordGrant(t *testing.T) {
+	t.Parallel()
+
+	stubClient := newStubHTTPClient()
+	stubClient.resps = append(stubClient.resps,
+		tokenRespWith(oauth2Resp{TokenType: "bearer", AccessToken: "expiring-token", ExpiresIn: 10, RefreshToken: "some-refresh-token"}), //nolint:gosec
+		&http.Response{StatusCode: 200, Body: io.NopCloser(&bytes.Buffer{})},
+	)
+	stubClient.errs = append(stubClient.errs, nil, nil)
+
+	c := client.NewOauth2HTTPClient(
+		"http://oauth2.something.com",
+		"client",
+		"client-secret",
+		client.WithOauth2HTTPClient(stubClient),
+		client.WithOauth2HTTPUser("user", "user-password"),
+	)
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest("GET", "http://some-target.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := c.Do(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(stubClient.bodies) != 2 {
+		t.Fatalf("expected two token requests: %d", len(stubClient.bodies))
+	}
+
+	query, err := url.ParseQuery(string(stubClient.bodies[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if query.Get("grant_type") != "refresh_token" {
+		t.Fatalf("expected grant_type to equal refresh_token: %s", query.Get("grant_type"))
+	}
+
+	if query.Get("refresh_token") != "some-refresh-token" {
+		t.Fatalf("expected refresh_token to equal some-refresh-token: %s", query.Get("refresh_token"))
+	}
+
+	if query.Get("password") != "" {
+		t.Fatal("expected password to not be sent")
+	}
+}
+
+func TestOauth2HTTPClientRetriesOnceAfterUnauthorized(t *testing.T) {
+	t.Parallel()
+
+	stubClient := newStubHTTPClient()
+	stubClient.resps = append(stubClient.resps,
+		tokenResp(),
+		&http.Response{StatusCode: 401, Body: io.NopCloser(&bytes.Buffer{})},
+		tokenResp(),
+		&http.Response{StatusCode: 200, Body: io.NopCloser(&bytes.Buffer{})},
+	)
+	stubClient.errs = append(stubClient.errs, nil, nil, nil, nil)
+
+	c := client.NewOauth2HTTPClient(
+		"http://oauth2.something.com",
+		"my-user",
+		"my-password",
+		client.WithOauth2HTTPClient(stubClient),
+	)
+
+	req, err := http.NewRequest("POST", "http://some-target.com", strings.NewReader("some-body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status code to be 200: %d", resp.StatusCode)
+	}
+
+	if len(stubClient.reqs) != 4 {
+		t.Fatalf("expected to get the token again and retry: %d", len(stubClient.reqs))
+	}
+
+	if string(stubClient.bodies[3]) != "some-body" {
+		t.Fatalf("expected the body to be sent again: %s", stubClient.bodies[3])
+	}
+}
+
+func TestOauth2HTTPClientWithRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	stubClient := newStubHTTPClient()
+	stubClient.resps = append(stubClient.resps,
+		tokenResp(),
+		&http.Response{StatusCode: 200, Body: io.NopCloser(&bytes.Buffer{})},
+		&http.Response{StatusCode: 401, Body: io.NopCloser(&bytes.Buffer{})},
+		&http.Response{StatusCode: 401, Body: io.NopCloser(&bytes.Buffer{})},
+	)
+	stubClient.errs = append(stubClient.errs, nil, nil, nil, nil)
+
+	c := client.NewOauth2HTTPClient(
+		"http://oauth2.something.com",
+		"cf",
+		"",
+		client.WithOauth2HTTPClient(stubClient),
+		client.WithOauth2RefreshToken("some-refresh-token"),
+		client.WithOauth2TokenPath("/custom/token"),
+	)
+
+	req, err := http.NewRequest("GET", "http://some-target.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if stubClient.reqs[0].URL.Path != "/custom/token" {
+		t.Fatalf("expected Path to equal /custom/token: %s", stubClient.reqs[0].URL.Path)
+	}
+
+	query, err := url.ParseQuery(string(stubClient.bodies[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if query.Get("grant_type") != "refresh_token" {
+		t.Fatalf("expected grant_type to equal refresh_token: %s", query.Get("grant_type"))
+	}
+
+	if query.Get("refresh_token") != "some-refresh-token" {
+		t.Fatalf("expected refresh_token to equal some-refresh-token: %s", query.Get("refresh_token"))
+	}
+
+	// The token is rejected and so is the refresh token.
+	req, err = http.NewRequest("GET", "http://some-target.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Do(req); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestOauth2HTTPClientWithJWTBearer(t *testing.T) {
+	t.Parallel()
+
+	stubClient := newStubHTTPClient()
+
+	c := client.NewOauth2HTTPClient(
+		"http://oauth2.something.com",
+		"client",
+		"client-secret",
+		client.WithOauth2HTTPClient(stubClient),
+		client.WithOauth2JWTBearer(func() (string, error) {
+			return "some-assertion", nil
+		}),
+	)
+
+	req, err := http.NewRequest("GET", "http://some-target.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	query, err := url.ParseQuery(string(stubClient.bodies[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if query.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+		t.Fatalf("expected grant_type to equal jwt-bearer: %s", query.Get("grant_type"))
+	}
+
+	if query.Get("assertion") != "some-assertion" {
+		t.Fatalf("expected assertion to equal some-assertion: %s", query.Get("assertion"))
+	}
+
+	if stubClient.reqs[1].Header.Get("Authorization") != "bearer some-token" {
+		t.Fatalf("expected Authorization header to equal bearer some-token: %s", stubClient.reqs[1].Header.Get("Authorization"))
+	}
+}
+
+func TestOauth2HTTPClientWithTokenSource(t *testing.T) {
+	t.Parallel()
+
+	stubClient := newStubHTTPClient()
+
+	var calls int
+	c := client.NewOauth2HTTPClient(
+		"",
+		"",
+		"",
+		client.WithOauth2HTTPClient(stubClient),
+		client.WithOauth2TokenSource(client.Oauth2TokenSourceFunc(func() (*client.Oauth2Token, error) {
+			calls++
+			return &client.Oauth2Token{
+				AccessToken: fmt.Sprintf("token-%d", calls),
+				Expiry:      time.Now().Add(time.Hour),
+			}, nil
+		})),
+	)
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest("GET", "http://some-target.com", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := c.Do(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected token to be reused: %d", calls)
+	}
+
+	if len(stubClient.reqs) != 2 {
+		t.Fatalf("expected no requests to an Oauth2 server: %d", len(stubClient.reqs))
+	}
+
+	if stubClient.reqs[1].Header.Get("Authorization") != "Bearer token-1" {
+		t.Fatalf("expected Authorization header to equal Bearer token-1: %s", stubClient.reqs[1].Header.Get("Authorization"))
+	}
+}
+
+func TestOauth2HTTPClientWithXTokenSource(t *testing.T) {
+	t.Parallel()
+
+	stubClient := newStubHTTPClient()
+	c := client.NewOauth2HTTPClient(
+		"",
+		"",
+		"",
+		client.WithOauth2HTTPClient(stubClient),
+		client.WithOauth2XTokenSource(oauth2.StaticTokenSource(&oauth2.Token{
+			AccessToken: "some-token",
+			TokenType:   "bearer",
+		})),
+	)
+
+	req, err := http.NewRequest("GET", "http://some-target.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stubClient.reqs) != 1 {
+		t.Fatalf("expected no requests to an Oauth2 server: %d", len(stubClient.reqs))
+	}
+
+	if stubClient.reqs[0].Header.Get("Authorization") != "Bearer some-token" {
+		t.Fatalf("expected Authorization header to equal Bearer some-token: %s", stubClient.reqs[0].Header.Get("Authorization"))
+	}
+}
+
+func TestOauth2HTTPClientSharesTokenRequestsUnderConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var tokenReqs atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			tokenReqs.Add(1)
+			time.Sleep(10 * time.Millisecond)
+			w.Write([]byte(`{"token_type":"bearer","access_token":"some-token"}`)) //nolint:errcheck
+			return
+		}
+
+		// Simulate an unrelated outage for every other request.
+		if r.URL.Query().Get("i") == "0" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	c := client.NewOauth2HTTPClient(server.URL, "my-user", "my-password")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 20; j++ {
+				req, err := http.NewRequest("GET", fmt.Sprintf("%s/some-path?i=%d", server.URL, j%2), nil)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				resp, err := c.Do(req)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				resp.Body.Close() //nolint:errcheck
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := tokenReqs.Load(); n != 1 {
+		t.Fatalf("expected to only get the token once: %d", n)
+	}
+}
+
 type stubHTTPClient struct {
 	mu     sync.Mutex
 	reqs   []*http.Request
@@ -414,10 +790,14 @@ func (s *stubHTTPClient) Do(r *http.Request) (*http.Response, error) {
 }
 
 func tokenResp() *http.Response {
-	data, err := json.Marshal(oauth2Resp{ //nolint:gosec
+	return tokenRespWith(oauth2Resp{ //nolint:gosec
 		TokenType:   "bearer",
 		AccessToken: "some-token",
 	})
+}
+
+func tokenRespWith(r oauth2Resp) *http.Response {
+	data, err := json.Marshal(r) //nolint:gosec
 	if err != nil {
 		panic(err)
 	}
@@ -429,6 +809,8 @@ func tokenResp() *http.Response {
 }
 
 type oauth2Resp struct {
-	TokenType   string `json:"token_type"`
-	AccessToken string `json:"access_token"` //nolint:gosec
+	TokenType    string `json:"token_type"`
+	AccessToken  string `json:"access_token"`            //nolint:gosec
+	RefreshToken string `json:"refresh_token,omitempty"` //nolint:gosec
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
 }
@@ -0,0 +1,442 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+
+	"code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
+
+	"google.golang.org/grpc"
+)
+
+// OrchestrationClient manages the hash ranges assigned to LogCache nodes via
+// the Orchestration gRPC API. Each LogCache node is addressed individually.
+type OrchestrationClient struct {
+	dialOpts []grpc.DialOption
+
+	mu     sync.Mutex
+	conns  map[string]*grpc.ClientConn
+	closed bool
+}
+
+// NewOrchestrationClient creates an OrchestrationClient.
+func NewOrchestrationClient(opts ...OrchestrationOption) *OrchestrationClient {
+	c := &OrchestrationClient{
+		conns: make(map[string]*grpc.ClientConn),
+	}
+
+	for _, o := range opts {
+		o.configure(c)
+	}
+
+	return c
+}
+
+// OrchestrationOption configures the OrchestrationClient.
+type OrchestrationOption interface {
+	configure(c *OrchestrationClient)
+}
+
+// WithOrchestrationDialOptions sets the gRPC dial options used to connect to
+// each LogCache node.
+func WithOrchestrationDialOptions(opts ...grpc.DialOption) OrchestrationOption {
+	return orchestrationOptionFunc(func(c *OrchestrationClient) {
+		c.dialOpts = append(c.dialOpts, opts...)
+	})
+}
+
+// orchestrationOptionFunc enables a function to be an OrchestrationOption.
+type orchestrationOptionFunc func(c *OrchestrationClient)
+
+// configure implements OrchestrationOption.
+func (f orchestrationOptionFunc) configure(c *OrchestrationClient) {
+	f(c)
+}
+
+// AddRange assigns the given range to the node.
+func (c *OrchestrationClient) AddRange(ctx context.Context, node string, r *logcache_v1.Range) error {
+	if err := ValidateRange(r); err != nil {
+		return err
+	}
+
+	oc, err := c.client(node)
+	if err != nil {
+		return err
+	}
+
+	_, err = oc.AddRange(ctx, &logcache_v1.AddRangeRequest{Range: r})
+	return err
+}
+
+// RemoveRange removes the given range from the node.
+func (c *OrchestrationClient) RemoveRange(ctx context.Context, node string, r *logcache_v1.Range) error {
+	if err := ValidateRange(r); err != nil {
+		return err
+	}
+
+	oc, err := c.client(node)
+	if err != nil {
+		return err
+	}
+
+	_, err = oc.RemoveRange(ctx, &logcache_v1.RemoveRangeRequest{Range: r})
+	return err
+}
+
+// ListRanges returns the ranges assigned to the node.
+func (c *OrchestrationClient) ListRanges(ctx context.Context, node string) ([]*logcache_v1.Range, error) {
+	oc, err := c.client(node)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := oc.ListRanges(ctx, &logcache_v1.ListRangesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.GetRanges(), nil
+}
+
+// SetRanges sends the entire assignment to the node. The assignment must be
+// valid.
+func (c *OrchestrationClient) SetRanges(ctx context.Context, node string, a RangeAssignment) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+
+	oc, err := c.client(node)
+	if err != nil {
+		return err
+	}
+
+	_, err = oc.SetRanges(ctx, &logcache_v1.SetRangesRequest{Ranges: a.proto()})
+	return err
+}
+
+// Assignment lists the ranges of each of the given nodes.
+func (c *OrchestrationClient) Assignment(ctx context.Context, nodes ...string) (RangeAssignment, error) {
+	a := make(RangeAssignment, len(nodes))
+	for _, n := range nodes {
+		ranges, err := c.ListRanges(ctx, n)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list ranges of %s: %w", n, err)
+		}
+
+		a[n] = ranges
+	}
+
+	return a, nil
+}
+
+// Plan compares the current assignment of the given nodes with the desired
+// assignment. Nodes that are only present in the desired assignment are
+// included in the plan.
+func (c *OrchestrationClient) Plan(ctx context.Context, nodes []string, desired RangeAssignment) (*RangePlan, error) {
+	if err := desired.Validate(); err != nil {
+		return nil, err
+	}
+
+	current, err := c.Assignment(ctx, nodes...)
+	if err != nil {
+		return nil, err
+	}
+
+	all := desired.Nodes()
+	for _, n := range nodes {
+		if !slices.Contains(all, n) {
+			all = append(all, n)
+		}
+	}
+	sort.Strings(all)
+
+	return &RangePlan{
+		Nodes:   all,
+		Desired: desired,
+		Changes: DiffRangeAssignments(current, desired),
+	}, nil
+}
+
+// Apply sends the desired assignment of the plan to each node, one node at a
+// time and in the order of the plan. It stops at the first node that fails.
+// Once every node is updated, the connections to the nodes that are not part
+// of the desired assignment are closed, as they have left the cluster.
+func (c *OrchestrationClient) Apply(ctx context.Context, p *RangePlan) error {
+	if err := p.Desired.Validate(); err != nil {
+		return err
+	}
+
+	for i, n := range p.Nodes {
+		if err := c.SetRanges(ctx, n, p.Desired); err != nil {
+			return fmt.Errorf("failed to set ranges on %s (applied to %d of %d nodes): %w", n, i, len(p.Nodes), err)
+		}
+	}
+
+	var errs []error
+	for _, n := range p.Nodes {
+		if _, ok := p.Desired[n]; !ok {
+			errs = append(errs, c.closeNode(n))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close closes the connections to all nodes. The OrchestrationClient must
+// not be used afterwards.
+func (c *OrchestrationClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+
+	var errs []error
+	for n, conn := range c.conns {
+		errs = append(errs, conn.Close())
+		delete(c.conns, n)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *OrchestrationClient) client(node string) (logcache_v1.OrchestrationClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, errors.New("orchestration client is closed")
+	}
+
+	if conn, ok := c.conns[node]; ok {
+		return logcache_v1.NewOrchestrationClient(conn), nil
+	}
+
+	conn, err := grpc.NewClient(node, c.dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s via gRPC: %w", node, err)
+	}
+
+	c.conns[node] = conn
+	return logcache_v1.NewOrchestrationClient(conn), nil
+}
+
+// closeNode closes and forgets the connection to the node, if there is one.
+func (c *OrchestrationClient) closeNode(node string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	conn, ok := c.conns[node]
+	if !ok {
+		return nil
+	}
+
+	delete(c.conns, node)
+	return conn.Close()
+}
+
+// RangeAssignment maps the address of each LogCache node to the hash ranges
+// it is responsible for.
+type RangeAssignment map[string][]*logcache_v1.Range
+
+// NewEvenRangeAssignment splits the entire hash space into equally sized
+// ranges, one for each of the given nodes. Duplicate nodes are only assigned
+// a range once.
+func NewEvenRangeAssignment(nodes ...string) RangeAssignment {
+	a := make(RangeAssignment, len(nodes))
+	unique := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		if _, ok := a[n]; ok {
+			continue
+		}
+		a[n] = nil
+		unique = append(unique, n)
+	}
+
+	if len(unique) == 0 {
+		return a
+	}
+
+	width := math.MaxUint64 / uint64(len(unique)) //nolint:gosec
+	var start uint64
+	for i, n := range unique {
+		end := start + width - 1
+		if i == len(unique)-1 {
+			end = math.MaxUint64
+		}
+
+		a[n] = []*logcache_v1.Range{{Start: start, End: end}}
+		start = end + 1
+	}
+
+	return a
+}
+
+// Nodes returns the sorted addresses of the assignment.
+func (a RangeAssignment) Nodes() []string {
+	var nodes []string
+	for n := range a {
+		nodes = append(nodes, n)
+	}
+	sort.Strings(nodes)
+
+	return nodes
+}
+
+// Validate ensures that the ranges of all nodes are valid, do not overlap and
+// cover the entire hash space.
+func (a RangeAssignment) Validate() error {
+	var all []*logcache_v1.Range
+	owners := make(map[*logcache_v1.Range]string)
+	for n, ranges := range a {
+		for _, r := range ranges {
+			if err := ValidateRange(r); err != nil {
+				return fmt.Errorf("invalid range for %s: %w", n, err)
+			}
+
+			owners[r] = n
+			all = append(all, r)
+		}
+	}
+
+	return validateRanges(all, func(r *logcache_v1.Range) string {
+		return fmt.Sprintf("%s (%s)", formatRange(r), owners[r])
+	})
+}
+
+func (a RangeAssignment) proto() map[string]*logcache_v1.Ranges {
+	m := make(map[string]*logcache_v1.Ranges, len(a))
+	for n, ranges := range a {
+		m[n] = &logcache_v1.Ranges{Ranges: ranges}
+	}
+
+	return m
+}
+
+// ValidateRange ensures that the range is set and that its start does not
+// exceed its end.
+func ValidateRange(r *logcache_v1.Range) error {
+	if r == nil {
+		return errors.New("range is not set")
+	}
+
+	if r.GetStart() > r.GetEnd() {
+		return fmt.Errorf("range %s starts after it ends", formatRange(r))
+	}
+
+	return nil
+}
+
+// ValidateRanges ensures that each range is valid and that together the
+// ranges cover the entire hash space without overlapping.
+func ValidateRanges(ranges []*logcache_v1.Range) error {
+	for _, r := range ranges {
+		if err := ValidateRange(r); err != nil {
+			return err
+		}
+	}
+
+	return validateRanges(ranges, formatRange)
+}
+
+func validateRanges(ranges []*logcache_v1.Range, name func(*logcache_v1.Range) string) error {
+	if len(ranges) == 0 {
+		return errors.New("ranges do not cover the hash space")
+	}
+
+	sorted := slices.Clone(ranges)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].GetStart() < sorted[j].GetStart()
+	})
+
+	if sorted[0].GetStart() != 0 {
+		return fmt.Errorf("hashes [0..%d] are not covered", sorted[0].GetStart()-1)
+	}
+
+	for i := 1; i < len(sorted); i++ {
+		prev, cur := sorted[i-1], sorted[i]
+		if cur.GetStart() <= prev.GetEnd() {
+			return fmt.Errorf("range %s overlaps with %s", name(cur), name(prev))
+		}
+
+		if cur.GetStart() != prev.GetEnd()+1 {
+			return fmt.Errorf("hashes [%d..%d] are not covered", prev.GetEnd()+1, cur.GetStart()-1)
+		}
+	}
+
+	if last := sorted[len(sorted)-1]; last.GetEnd() != math.MaxUint64 {
+		return fmt.Errorf("hashes [%d..%d] are not covered", last.GetEnd()+1, uint64(math.MaxUint64))
+	}
+
+	return nil
+}
+
+func formatRange(r *logcache_v1.Range) string {
+	return fmt.Sprintf("[%d..%d]", r.GetStart(), r.GetEnd())
+}
+
+// RangeChange describes how the ranges of a single node change.
+type RangeChange struct {
+	Node    string
+	Added   []*logcache_v1.Range
+	Removed []*logcache_v1.Range
+}
+
+// RangePlan is the set of changes required to move from the current to the
+// desired assignment. It is created by OrchestrationClient.Plan.
+type RangePlan struct {
+	// Nodes are the nodes that receive the desired assignment, in the order
+	// in which it is applied.
+	Nodes   []string
+	Desired RangeAssignment
+	Changes []RangeChange
+}
+
+// DiffRangeAssignments returns the changes for every node whose ranges
+// differ between the current and desired assignment, sorted by node.
+func DiffRangeAssignments(current, desired RangeAssignment) []RangeChange {
+	nodes := current.Nodes()
+	for _, n := range desired.Nodes() {
+		if _, ok := current[n]; !ok {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.Strings(nodes)
+
+	var changes []RangeChange
+	for _, n := range nodes {
+		added := subtractRanges(desired[n], current[n])
+		removed := subtractRanges(current[n], desired[n])
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		changes = append(changes, RangeChange{
+			Node:    n,
+			Added:   added,
+			Removed: removed,
+		})
+	}
+
+	return changes
+}
+
+// subtractRanges returns the ranges of a that are not in b.
+func subtractRanges(a, b []*logcache_v1.Range) []*logcache_v1.Range {
+	var result []*logcache_v1.Range
+	for _, r := range a {
+		found := slices.ContainsFunc(b, func(x *logcache_v1.Range) bool {
+			return x.GetStart() == r.GetStart() && x.GetEnd() == r.GetEnd()
+		})
+
+		if !found {
+			result = append(result, r)
+		}
+	}
+
+	return result
+}
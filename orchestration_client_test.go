@@ -0,0 +1,397 @@
+package client_test
+
+import (
+	"context"
+	"math"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	client "code.cloudfoundry.org/go-log-cache/v3"
+
+	rpc "code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestValidateRanges(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		ranges []*rpc.Range
+		valid  bool
+	}{
+		{"full coverage", []*rpc.Range{{Start: 0, End: 99}, {Start: 100, End: math.MaxUint64}}, true},
+		{"unordered", []*rpc.Range{{Start: 100, End: math.MaxUint64}, {Start: 0, End: 99}}, true},
+		{"single", []*rpc.Range{{Start: 0, End: math.MaxUint64}}, true},
+		{"empty", nil, false},
+		{"nil range", []*rpc.Range{nil}, false},
+		{"start after end", []*rpc.Range{{Start: 100, End: 0}}, false},
+		{"overlap", []*rpc.Range{{Start: 0, End: 100}, {Start: 100, End: math.MaxUint64}}, false},
+		{"gap", []*rpc.Range{{Start: 0, End: 98}, {Start: 100, End: math.MaxUint64}}, false},
+		{"missing start", []*rpc.Range{{Start: 1, End: math.MaxUint64}}, false},
+		{"missing end", []*rpc.Range{{Start: 0, End: math.MaxUint64 - 1}}, false},
+	}
+
+	for _, tt := range tests {
+		err := client.ValidateRanges(tt.ranges)
+		if tt.valid && err != nil {
+			t.Errorf("%s: expected ranges to be valid: %s", tt.name, err)
+		}
+
+		if !tt.valid && err == nil {
+			t.Errorf("%s: expected ranges to be invalid", tt.name)
+		}
+	}
+}
+
+func TestRangeAssignmentValidateDetectsOverlapAcrossNodes(t *testing.T) {
+	t.Parallel()
+
+	a := client.RangeAssignment{
+		"node-a": {{Start: 0, End: 100}},
+		"node-b": {{Start: 50, End: math.MaxUint64}},
+	}
+
+	if err := a.Validate(); err == nil {
+		t.Fatal("expected overlapping ranges to be invalid")
+	}
+}
+
+func TestNewEvenRangeAssignment(t *testing.T) {
+	t.Parallel()
+
+	a := client.NewEvenRangeAssignment("node-a", "node-b", "node-c")
+	if err := a.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(a) != 3 {
+		t.Fatalf("expected 3 nodes: %d", len(a))
+	}
+}
+
+func TestNewEvenRangeAssignmentIgnoresDuplicateNodes(t *testing.T) {
+	t.Parallel()
+
+	a := client.NewEvenRangeAssignment("node-a", "node-b", "node-a")
+	if err := a.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(a) != 2 {
+		t.Fatalf("expected 2 nodes: %d", len(a))
+	}
+
+	if r := a["node-b"]; len(r) != 1 || r[0].End != math.MaxUint64 {
+		t.Fatalf("expected node-b to cover the end of the hash space: %v", r)
+	}
+}
+
+func TestDiffRangeAssignments(t *testing.T) {
+	t.Parallel()
+
+	current := client.RangeAssignment{
+		"node-a": {{Start: 0, End: 99}, {Start: 100, End: 199}},
+		"node-b": {{Start: 200, End: math.MaxUint64}},
+	}
+	desired := client.RangeAssignment{
+		"node-a": {{Start: 0, End: 99}},
+		"node-b": {{Start: 200, End: math.MaxUint64}},
+		"node-c": {{Start: 100, End: 199}},
+	}
+
+	changes := client.DiffRangeAssignments(current, desired)
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes: %d", len(changes))
+	}
+
+	if changes[0].Node != "node-a" || len(changes[0].Added) != 0 || len(changes[0].Removed) != 1 {
+		t.Fatalf("wrong change for node-a: %+v", changes[0])
+	}
+
+	if changes[1].Node != "node-c" || len(changes[1].Added) != 1 || len(changes[1].Removed) != 0 {
+		t.Fatalf("wrong change for node-c: %+v", changes[1])
+	}
+}
+
+func TestOrchestrationClientAddAndListRanges(t *testing.T) {
+	t.Parallel()
+
+	s := newStubOrchestration(t)
+	c := client.NewOrchestrationClient(client.WithOrchestrationDialOptions(insecureOpt))
+
+	err := c.AddRange(context.Background(), s.addr(), &rpc.Range{Start: 1, End: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ranges, err := c.ListRanges(context.Background(), s.addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ranges) != 1 || ranges[0].Start != 1 || ranges[0].End != 2 {
+		t.Fatalf("wrong ranges: %v", ranges)
+	}
+
+	err = c.RemoveRange(context.Background(), s.addr(), &rpc.Range{Start: 1, End: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ranges, err = c.ListRanges(context.Background(), s.addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ranges) != 0 {
+		t.Fatalf("expected ranges to be removed: %v", ranges)
+	}
+}
+
+func TestOrchestrationClientAddRangeRejectsInvalidRange(t *testing.T) {
+	t.Parallel()
+
+	s := newStubOrchestration(t)
+	c := client.NewOrchestrationClient(client.WithOrchestrationDialOptions(insecureOpt))
+
+	err := c.AddRange(context.Background(), s.addr(), &rpc.Range{Start: 2, End: 1})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if len(s.ranges) != 0 {
+		t.Fatal("expected request to not be sent")
+	}
+}
+
+func TestOrchestrationClientPlanAndApply(t *testing.T) {
+	t.Parallel()
+
+	a := newStubOrchestration(t)
+	b := newStubOrchestration(t)
+	a.ranges = []*rpc.Range{{Start: 0, End: math.MaxUint64}}
+
+	c := client.NewOrchestrationClient(client.WithOrchestrationDialOptions(insecureOpt))
+	desired := client.NewEvenRangeAssignment(a.addr(), b.addr())
+
+	p, err := c.Plan(context.Background(), []string{a.addr()}, desired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(p.Nodes) != 2 {
+		t.Fatalf("expected plan to include both nodes: %v", p.Nodes)
+	}
+
+	if len(p.Changes) != 2 {
+		t.Fatalf("expected 2 changes: %+v", p.Changes)
+	}
+
+	if err := c.Apply(context.Background(), p); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []*stubOrchestration{a, b} {
+		reqs := s.setRequests()
+		if len(reqs) != 1 {
+			t.Fatalf("expected 1 SetRanges request: %d", len(reqs))
+		}
+
+		for n, ranges := range desired {
+			got := reqs[0].GetRanges()[n].GetRanges()
+			if len(got) != len(ranges) || !proto.Equal(got[0], ranges[0]) {
+				t.Fatalf("wrong ranges for %s: %v", n, got)
+			}
+		}
+	}
+}
+
+func TestOrchestrationClientApplyRejectsInvalidAssignment(t *testing.T) {
+	t.Parallel()
+
+	s := newStubOrchestration(t)
+	c := client.NewOrchestrationClient(client.WithOrchestrationDialOptions(insecureOpt))
+
+	err := c.Apply(context.Background(), &client.RangePlan{
+		Nodes: []string{s.addr()},
+		Desired: client.RangeAssignment{
+			s.addr(): {{Start: 0, End: 100}},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if len(s.setRequests()) != 0 {
+		t.Fatal("expected request to not be sent")
+	}
+}
+
+func TestOrchestrationClientClosesConnections(t *testing.T) {
+	t.Parallel()
+
+	a := newStubOrchestration(t)
+	b := newStubOrchestration(t)
+	d := newSpyDialer()
+
+	c := client.NewOrchestrationClient(client.WithOrchestrationDialOptions(insecureOpt, grpc.WithContextDialer(d.dial)))
+
+	// b leaves the cluster.
+	p, err := c.Plan(context.Background(), []string{a.addr(), b.addr()}, client.NewEvenRangeAssignment(a.addr()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Apply(context.Background(), p); err != nil {
+		t.Fatal(err)
+	}
+
+	d.waitClosed(t, b.addr())
+	if d.open(a.addr()) == 0 {
+		t.Fatal("expected the connection to the remaining node to stay open")
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	d.waitClosed(t, a.addr())
+
+	if _, err := c.ListRanges(context.Background(), a.addr()); err == nil {
+		t.Fatal("expected the closed client to fail")
+	}
+}
+
+// spyDialer dials TCP connections and tracks which of them are open.
+type spyDialer struct {
+	mu    sync.Mutex
+	conns map[string]int
+}
+
+func newSpyDialer() *spyDialer {
+	return &spyDialer{conns: make(map[string]int)}
+}
+
+func (d *spyDialer) dial(ctx context.Context, addr string) (net.Conn, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.conns[addr]++
+
+	return &spyConn{Conn: conn, d: d, addr: addr}, nil
+}
+
+func (d *spyDialer) open(addr string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.conns[addr]
+}
+
+func (d *spyDialer) waitClosed(t *testing.T, addr string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for d.open(addr) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the connections to %s to be closed", addr)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+type spyConn struct {
+	net.Conn
+	d     *spyDialer
+	addr  string
+	close sync.Once
+}
+
+func (c *spyConn) Close() error {
+	c.close.Do(func() {
+		c.d.mu.Lock()
+		defer c.d.mu.Unlock()
+		c.d.conns[c.addr]--
+	})
+
+	return c.Conn.Close()
+}
+
+type stubOrchestration struct {
+	mu      sync.Mutex
+	ranges  []*rpc.Range
+	setReqs []*rpc.SetRangesRequest
+	lis     net.Listener
+	rpc.UnimplementedOrchestrationServer
+}
+
+func newStubOrchestration(t *testing.T) *stubOrchestration {
+	s := &stubOrchestration{}
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.lis = lis
+	srv := grpc.NewServer()
+	rpc.RegisterOrchestrationServer(srv, s)
+	go srv.Serve(lis) //nolint:errcheck
+	t.Cleanup(srv.Stop)
+
+	return s
+}
+
+func (s *stubOrchestration) addr() string {
+	return s.lis.Addr().String()
+}
+
+func (s *stubOrchestration) AddRange(ctx context.Context, r *rpc.AddRangeRequest) (*rpc.AddRangeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ranges = append(s.ranges, r.GetRange())
+
+	return &rpc.AddRangeResponse{}, nil
+}
+
+func (s *stubOrchestration) RemoveRange(ctx context.Context, r *rpc.RemoveRangeRequest) (*rpc.RemoveRangeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ranges []*rpc.Range
+	for _, x := range s.ranges {
+		if x.Start != r.GetRange().GetStart() || x.End != r.GetRange().GetEnd() {
+			ranges = append(ranges, x)
+		}
+	}
+	s.ranges = ranges
+
+	return &rpc.RemoveRangeResponse{}, nil
+}
+
+func (s *stubOrchestration) ListRanges(ctx context.Context, r *rpc.ListRangesRequest) (*rpc.ListRangesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return &rpc.ListRangesResponse{Ranges: s.ranges}, nil
+}
+
+func (s *stubOrchestration) SetRanges(ctx context.Context, r *rpc.SetRangesRequest) (*rpc.SetRangesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setReqs = append(s.setReqs, r)
+
+	return &rpc.SetRangesResponse{}, nil
+}
+
+func (s *stubOrchestration) setRequests() []*rpc.SetRangesRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*rpc.SetRangesRequest(nil), s.setReqs...)
+}
@@ -0,0 +1,216 @@
+package promql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var functionNameRE = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// Call is a function call, e.g. rate(cpu[5m]).
+type Call struct {
+	name string
+	args []Expr
+
+	// rangeArg is the index of the argument that has to be a range vector,
+	// or -1.
+	rangeArg int
+}
+
+// Func calls the function with the given name. Prefer the dedicated
+// constructors, e.g. Rate, as they validate the types of the arguments.
+func Func(name string, args ...Expr) *Call {
+	return &Call{name: name, args: args, rangeArg: -1}
+}
+
+func rangeFunc(name string, args ...Expr) *Call {
+	return &Call{name: name, args: args, rangeArg: len(args) - 1}
+}
+
+// Rate calls rate.
+func Rate(r *RangeSelector) *Call { return rangeFunc("rate", r) }
+
+// IRate calls irate.
+func IRate(r *RangeSelector) *Call { return rangeFunc("irate", r) }
+
+// Increase calls increase.
+func Increase(r *RangeSelector) *Call { return rangeFunc("increase", r) }
+
+// Delta calls delta.
+func Delta(r *RangeSelector) *Call { return rangeFunc("delta", r) }
+
+// AvgOverTime calls avg_over_time.
+func AvgOverTime(r *RangeSelector) *Call { return rangeFunc("avg_over_time", r) }
+
+// MinOverTime calls min_over_time.
+func MinOverTime(r *RangeSelector) *Call { return rangeFunc("min_over_time", r) }
+
+// MaxOverTime calls max_over_time.
+func MaxOverTime(r *RangeSelector) *Call { return rangeFunc("max_over_time", r) }
+
+// SumOverTime calls sum_over_time.
+func SumOverTime(r *RangeSelector) *Call { return rangeFunc("sum_over_time", r) }
+
+// CountOverTime calls count_over_time.
+func CountOverTime(r *RangeSelector) *Call { return rangeFunc("count_over_time", r) }
+
+// QuantileOverTime calls quantile_over_time.
+func QuantileOverTime(q float64, r *RangeSelector) *Call {
+	return rangeFunc("quantile_over_time", Number(q), r)
+}
+
+// HistogramQuantile calls histogram_quantile.
+func HistogramQuantile(q float64, e Expr) *Call {
+	return Func("histogram_quantile", Number(q), e)
+}
+
+// Abs calls abs.
+func Abs(e Expr) *Call { return Func("abs", e) }
+
+// Ceil calls ceil.
+func Ceil(e Expr) *Call { return Func("ceil", e) }
+
+// Floor calls floor.
+func Floor(e Expr) *Call { return Func("floor", e) }
+
+// String implements Expr.
+func (c *Call) String() string {
+	return c.name + "(" + joinExprs(c.args) + ")"
+}
+
+// Validate implements Expr.
+func (c *Call) Validate() error {
+	if !functionNameRE.MatchString(c.name) {
+		return fmt.Errorf("invalid function name %q", c.name)
+	}
+
+	for i, a := range c.args {
+		if a == nil {
+			return fmt.Errorf("missing argument %d of %s", i+1, c.name)
+		}
+
+		if i == c.rangeArg {
+			if r, ok := a.(*RangeSelector); !ok || r == nil {
+				return fmt.Errorf("%s expects a range vector", c.name)
+			}
+		}
+
+		if err := a.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Aggregation aggregates a vector, e.g. sum by (instance_id) (cpu).
+type Aggregation struct {
+	op      string
+	param   Expr
+	expr    Expr
+	labels  []string
+	without bool
+}
+
+// Sum aggregates via sum.
+func Sum(e Expr) *Aggregation { return &Aggregation{op: "sum", expr: e} }
+
+// Avg aggregates via avg.
+func Avg(e Expr) *Aggregation { return &Aggregation{op: "avg", expr: e} }
+
+// Min aggregates via min.
+func Min(e Expr) *Aggregation { return &Aggregation{op: "min", expr: e} }
+
+// Max aggregates via max.
+func Max(e Expr) *Aggregation { return &Aggregation{op: "max", expr: e} }
+
+// Count aggregates via count.
+func Count(e Expr) *Aggregation { return &Aggregation{op: "count", expr: e} }
+
+// TopK aggregates via topk.
+func TopK(k int, e Expr) *Aggregation {
+	return &Aggregation{op: "topk", param: Number(k), expr: e}
+}
+
+// BottomK aggregates via bottomk.
+func BottomK(k int, e Expr) *Aggregation {
+	return &Aggregation{op: "bottomk", param: Number(k), expr: e}
+}
+
+// Quantile aggregates via quantile.
+func Quantile(q float64, e Expr) *Aggregation {
+	return &Aggregation{op: "quantile", param: Number(q), expr: e}
+}
+
+// By returns a copy of the aggregation that preserves the given labels.
+func (a *Aggregation) By(labels ...string) *Aggregation {
+	c := *a
+	c.labels = labels
+	c.without = false
+	return &c
+}
+
+// Without returns a copy of the aggregation that drops the given labels.
+func (a *Aggregation) Without(labels ...string) *Aggregation {
+	c := *a
+	c.labels = labels
+	c.without = true
+	return &c
+}
+
+// String implements Expr.
+func (a *Aggregation) String() string {
+	var b strings.Builder
+	b.WriteString(a.op)
+
+	if len(a.labels) > 0 || a.without {
+		if a.without {
+			b.WriteString(" without (")
+		} else {
+			b.WriteString(" by (")
+		}
+		b.WriteString(strings.Join(a.labels, ", "))
+		b.WriteString(") ")
+	}
+
+	args := []Expr{a.expr}
+	if a.param != nil {
+		args = []Expr{a.param, a.expr}
+	}
+
+	b.WriteString("(" + joinExprs(args) + ")")
+	return b.String()
+}
+
+// Validate implements Expr.
+func (a *Aggregation) Validate() error {
+	for _, l := range a.labels {
+		if !labelNameRE.MatchString(l) {
+			return fmt.Errorf("invalid label name %q", l)
+		}
+	}
+
+	if a.expr == nil {
+		return fmt.Errorf("missing expression of %s", a.op)
+	}
+
+	if _, ok := a.expr.(*RangeSelector); ok {
+		return fmt.Errorf("%s expects an instant vector", a.op)
+	}
+
+	return a.expr.Validate()
+}
+
+func joinExprs(es []Expr) string {
+	s := make([]string, 0, len(es))
+	for _, e := range es {
+		if e == nil {
+			s = append(s, "")
+			continue
+		}
+		s = append(s, e.String())
+	}
+
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,230 @@
+// Package promql builds PromQL queries for LogCache. Label values are
+// escaped when the query is rendered, and metric and label names are
+// validated against the rules of Prometheus, e.g.:
+//
+//	q, err := promql.Build(
+//		promql.Sum(
+//			promql.Rate(promql.Metric("requests", promql.Eq("source_id", sourceID)).Range(5*time.Minute)),
+//		).By("instance_id"),
+//	)
+package promql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	metricNameRE = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRE  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// Expr is a PromQL expression.
+type Expr interface {
+	// String renders the expression. It does not validate it.
+	String() string
+
+	// Validate returns an error if the expression is not valid PromQL.
+	Validate() error
+}
+
+// Build validates and renders the expression.
+func Build(e Expr) (string, error) {
+	if err := e.Validate(); err != nil {
+		return "", err
+	}
+
+	return e.String(), nil
+}
+
+// MatchOp is the operator of a label Matcher.
+type MatchOp string
+
+// The operators of label matchers.
+const (
+	MatchEqual     MatchOp = "="
+	MatchNotEqual  MatchOp = "!="
+	MatchRegexp    MatchOp = "=~"
+	MatchNotRegexp MatchOp = "!~"
+)
+
+// Matcher matches the value of a label.
+type Matcher struct {
+	Label string
+	Op    MatchOp
+	Value string
+}
+
+// Eq matches labels that equal the value.
+func Eq(label, value string) Matcher {
+	return Matcher{Label: label, Op: MatchEqual, Value: value}
+}
+
+// NotEq matches labels that do not equal the value.
+func NotEq(label, value string) Matcher {
+	return Matcher{Label: label, Op: MatchNotEqual, Value: value}
+}
+
+// Re matches labels that match the regular expression. Like in Prometheus,
+// the regular expression is anchored.
+func Re(label, re string) Matcher {
+	return Matcher{Label: label, Op: MatchRegexp, Value: re}
+}
+
+// NotRe matches labels that do not match the regular expression.
+func NotRe(label, re string) Matcher {
+	return Matcher{Label: label, Op: MatchNotRegexp, Value: re}
+}
+
+// String renders the matcher with the value quoted and escaped.
+func (m Matcher) String() string {
+	return m.Label + string(m.Op) + strconv.Quote(m.Value)
+}
+
+// Validate implements Expr.
+func (m Matcher) Validate() error {
+	if !labelNameRE.MatchString(m.Label) {
+		return fmt.Errorf("invalid label name %q", m.Label)
+	}
+
+	switch m.Op {
+	case MatchEqual, MatchNotEqual:
+	case MatchRegexp, MatchNotRegexp:
+		if _, err := regexp.Compile("^(?:" + m.Value + ")$"); err != nil {
+			return fmt.Errorf("invalid regular expression for label %s: %s", m.Label, err)
+		}
+	default:
+		return fmt.Errorf("invalid match operator %q", m.Op)
+	}
+
+	return nil
+}
+
+// Selector is an instant vector selector, e.g. cpu{source_id="some-id"}.
+type Selector struct {
+	name     string
+	matchers []Matcher
+}
+
+// Metric selects the metric with the given name. An empty name selects
+// any metric, in which case at least one matcher has to be given.
+func Metric(name string, matchers ...Matcher) *Selector {
+	return &Selector{
+		name:     name,
+		matchers: matchers,
+	}
+}
+
+// Where returns a copy of the selector with the additional matchers.
+func (s *Selector) Where(matchers ...Matcher) *Selector {
+	return &Selector{
+		name:     s.name,
+		matchers: append(s.matchers[:len(s.matchers):len(s.matchers)], matchers...),
+	}
+}
+
+// Range selects the given duration of samples, e.g. cpu[5m].
+func (s *Selector) Range(d time.Duration) *RangeSelector {
+	return &RangeSelector{
+		selector: s,
+		duration: d,
+	}
+}
+
+// String implements Expr.
+func (s *Selector) String() string {
+	if len(s.matchers) == 0 {
+		return s.name
+	}
+
+	ms := make([]string, 0, len(s.matchers))
+	for _, m := range s.matchers {
+		ms = append(ms, m.String())
+	}
+
+	return s.name + "{" + strings.Join(ms, ", ") + "}"
+}
+
+// Validate implements Expr.
+func (s *Selector) Validate() error {
+	if s.name != "" && !metricNameRE.MatchString(s.name) {
+		return fmt.Errorf("invalid metric name %q", s.name)
+	}
+
+	if s.name == "" && len(s.matchers) == 0 {
+		return errors.New("selector needs a metric name or a label matcher")
+	}
+
+	for _, m := range s.matchers {
+		if err := m.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RangeSelector is a range vector selector, e.g. cpu[5m].
+type RangeSelector struct {
+	selector *Selector
+	duration time.Duration
+}
+
+// String implements Expr.
+func (r *RangeSelector) String() string {
+	return r.selector.String() + "[" + formatDuration(r.duration) + "]"
+}
+
+// Validate implements Expr.
+func (r *RangeSelector) Validate() error {
+	if r.duration <= 0 || r.duration%time.Millisecond != 0 {
+		return fmt.Errorf("invalid range %s: must be a positive number of milliseconds", r.duration)
+	}
+
+	return r.selector.Validate()
+}
+
+// formatDuration formats the duration like Prometheus, e.g. 1h30m or 500ms.
+func formatDuration(d time.Duration) string {
+	ms := d.Milliseconds()
+	if ms == 0 {
+		return "0s"
+	}
+
+	var b strings.Builder
+	for _, u := range []struct {
+		unit string
+		ms   int64
+	}{
+		{"d", 24 * 60 * 60 * 1000},
+		{"h", 60 * 60 * 1000},
+		{"m", 60 * 1000},
+		{"s", 1000},
+		{"ms", 1},
+	} {
+		if n := ms / u.ms; n > 0 {
+			b.WriteString(strconv.FormatInt(n, 10))
+			b.WriteString(u.unit)
+			ms -= n * u.ms
+		}
+	}
+
+	return b.String()
+}
+
+// Number is a number literal.
+type Number float64
+
+// String implements Expr.
+func (n Number) String() string {
+	return strconv.FormatFloat(float64(n), 'g', -1, 64)
+}
+
+// Validate implements Expr.
+func (n Number) Validate() error {
+	return nil
+}
@@ -0,0 +1,155 @@
+package promql_test
+
+import (
+	"testing"
+	"time"
+
+	"code.cloudfoundry.org/go-log-cache/v3/promql"
+)
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		expr     promql.Expr
+		expected string
+	}{
+		{
+			name:     "metric",
+			expr:     promql.Metric("cpu"),
+			expected: `cpu`,
+		},
+		{
+			name: "matchers",
+			expr: promql.Metric("cpu",
+				promql.Eq("source_id", "some-id"),
+				promql.NotEq("deployment", "cf"),
+				promql.Re("job", "router|api"),
+				promql.NotRe("index", "[0-3]"),
+			),
+			expected: `cpu{source_id="some-id", deployment!="cf", job=~"router|api", index!~"[0-3]"}`,
+		},
+		{
+			name:     "escaped label values",
+			expr:     promql.Metric("cpu", promql.Eq("source_id", `a"b\c`+"\n")),
+			expected: `cpu{source_id="a\"b\\c\n"}`,
+		},
+		{
+			name:     "escaped regular expressions",
+			expr:     promql.Metric("cpu", promql.Re("source_id", `a\.b`)),
+			expected: `cpu{source_id=~"a\\.b"}`,
+		},
+		{
+			name:     "no metric name",
+			expr:     promql.Metric("", promql.Eq("__name__", "cpu")),
+			expected: `{__name__="cpu"}`,
+		},
+		{
+			name:     "where",
+			expr:     promql.Metric("cpu", promql.Eq("a", "1")).Where(promql.Eq("b", "2")),
+			expected: `cpu{a="1", b="2"}`,
+		},
+		{
+			name:     "range",
+			expr:     promql.Metric("cpu").Range(90*time.Minute + 500*time.Millisecond),
+			expected: `cpu[1h30m500ms]`,
+		},
+		{
+			name:     "range of days",
+			expr:     promql.Metric("cpu").Range(49 * time.Hour),
+			expected: `cpu[2d1h]`,
+		},
+		{
+			name:     "function",
+			expr:     promql.Rate(promql.Metric("requests", promql.Eq("source_id", "some-id")).Range(5 * time.Minute)),
+			expected: `rate(requests{source_id="some-id"}[5m])`,
+		},
+		{
+			name:     "function with parameter",
+			expr:     promql.QuantileOverTime(0.99, promql.Metric("latency").Range(time.Minute)),
+			expected: `quantile_over_time(0.99, latency[1m])`,
+		},
+		{
+			name:     "generic function",
+			expr:     promql.Func("clamp_max", promql.Metric("cpu"), promql.Number(100)),
+			expected: `clamp_max(cpu, 100)`,
+		},
+		{
+			name:     "aggregation",
+			expr:     promql.Sum(promql.Metric("cpu")),
+			expected: `sum(cpu)`,
+		},
+		{
+			name:     "aggregation by",
+			expr:     promql.Avg(promql.IRate(promql.Metric("cpu").Range(time.Minute))).By("source_id", "instance_id"),
+			expected: `avg by (source_id, instance_id) (irate(cpu[1m]))`,
+		},
+		{
+			name:     "aggregation without",
+			expr:     promql.Max(promql.Metric("cpu")).Without("instance_id"),
+			expected: `max without (instance_id) (cpu)`,
+		},
+		{
+			name:     "aggregation with parameter",
+			expr:     promql.TopK(5, promql.Metric("cpu")).By("source_id"),
+			expected: `topk by (source_id) (5, cpu)`,
+		},
+		{
+			name:     "histogram quantile",
+			expr:     promql.HistogramQuantile(0.95, promql.Sum(promql.Rate(promql.Metric("latency_bucket").Range(5*time.Minute))).By("le")),
+			expected: `histogram_quantile(0.95, sum by (le) (rate(latency_bucket[5m])))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := promql.Build(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if q != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, q)
+			}
+		})
+	}
+}
+
+func TestBuildValidates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		expr promql.Expr
+	}{
+		{"invalid metric name", promql.Metric("cpu-usage")},
+		{"metric name starting with digit", promql.Metric("1cpu")},
+		{"no metric name or matchers", promql.Metric("")},
+		{"invalid label name", promql.Metric("cpu", promql.Eq("source:id", "a"))},
+		{"label name starting with digit", promql.Metric("cpu", promql.Eq("1a", "a"))},
+		{"invalid regular expression", promql.Metric("cpu", promql.Re("a", "("))},
+		{"invalid operator", promql.Metric("cpu", promql.Matcher{Label: "a", Op: "==", Value: "a"})},
+		{"zero range", promql.Metric("cpu").Range(0)},
+		{"sub-millisecond range", promql.Metric("cpu").Range(time.Microsecond)},
+		{"invalid selector in range", promql.Metric("cpu-usage").Range(time.Minute)},
+		{"missing range vector", promql.Rate(nil)},
+		{"invalid function name", promql.Func("rate(cpu[1m])")},
+		{"missing argument", promql.Abs(nil)},
+		{"invalid grouping label", promql.Sum(promql.Metric("cpu")).By("source-id")},
+		{"range vector for aggregation", promql.Sum(promql.Metric("cpu").Range(time.Minute))},
+		{"missing aggregated expression", promql.Sum(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := promql.Build(tt.expr); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PromQLPoint is a single value of a PromQL result.
+type PromQLPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// PromQLSample is an element of a PromQL vector.
+type PromQLSample struct {
+	Metric map[string]string
+	Point  PromQLPoint
+}
+
+// PromQLSeries is an element of a PromQL matrix.
+type PromQLSeries struct {
+	Metric map[string]string
+	Points []PromQLPoint
+}
+
+// PromQLString is a PromQL string result.
+type PromQLString struct {
+	Time  time.Time
+	Value string
+}
+
+// AsVector decodes the result of a query with the result type "vector".
+func (r *PromQLQueryResult) AsVector() ([]PromQLSample, error) {
+	var raw []struct {
+		Metric map[string]string `json:"metric"`
+		Value  json.RawMessage   `json:"value"`
+	}
+	if err := r.decodeResult("vector", &raw); err != nil {
+		return nil, err
+	}
+
+	samples := make([]PromQLSample, 0, len(raw))
+	for _, s := range raw {
+		p, err := decodePromQLPoint(s.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		samples = append(samples, PromQLSample{
+			Metric: s.Metric,
+			Point:  p,
+		})
+	}
+
+	return samples, nil
+}
+
+// AsMatrix decodes the result of a query with the result type "matrix".
+func (r *PromQLQueryResult) AsMatrix() ([]PromQLSeries, error) {
+	var raw []struct {
+		Metric map[string]string `json:"metric"`
+		Values []json.RawMessage `json:"values"`
+	}
+	if err := r.decodeResult("matrix", &raw); err != nil {
+		return nil, err
+	}
+
+	series := make([]PromQLSeries, 0, len(raw))
+	for _, s := range raw {
+		points := make([]PromQLPoint, 0, len(s.Values))
+		for _, v := range s.Values {
+			p, err := decodePromQLPoint(v)
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, p)
+		}
+
+		series = append(series, PromQLSeries{
+			Metric: s.Metric,
+			Points: points,
+		})
+	}
+
+	return series, nil
+}
+
+// AsScalar decodes the result of a query with the result type "scalar".
+func (r *PromQLQueryResult) AsScalar() (PromQLPoint, error) {
+	var raw json.RawMessage
+	if err := r.decodeResult("scalar", &raw); err != nil {
+		return PromQLPoint{}, err
+	}
+
+	return decodePromQLPoint(raw)
+}
+
+// AsString decodes the result of a query with the result type "string".
+func (r *PromQLQueryResult) AsString() (PromQLString, error) {
+	var raw json.RawMessage
+	if err := r.decodeResult("string", &raw); err != nil {
+		return PromQLString{}, err
+	}
+
+	t, v, err := decodePromQLPair(raw)
+	if err != nil {
+		return PromQLString{}, err
+	}
+
+	return PromQLString{
+		Time:  t,
+		Value: v,
+	}, nil
+}
+
+func (r *PromQLQueryResult) decodeResult(resultType string, v any) error {
+	if r.Data.ResultType != resultType {
+		return fmt.Errorf("result type is %q, not %q", r.Data.ResultType, resultType)
+	}
+
+	if err := json.Unmarshal(r.Data.Result, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %s", resultType, err)
+	}
+
+	return nil
+}
+
+// decodePromQLPoint decodes a [<time>, "<value>"] pair. The value may be
+// "NaN", "+Inf" or "-Inf".
+func decodePromQLPoint(data []byte) (PromQLPoint, error) {
+	t, v, err := decodePromQLPair(data)
+	if err != nil {
+		return PromQLPoint{}, err
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return PromQLPoint{}, fmt.Errorf("failed to parse value: %s", err)
+	}
+
+	return PromQLPoint{
+		Time:  t,
+		Value: f,
+	}, nil
+}
+
+func decodePromQLPair(data []byte) (time.Time, string, error) {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return time.Time{}, "", err
+	}
+
+	if len(pair) != 2 {
+		return time.Time{}, "", fmt.Errorf("invalid length of point, got %d, expected 2", len(pair))
+	}
+
+	d := json.NewDecoder(bytes.NewReader(pair[0]))
+	d.UseNumber()
+
+	var n json.Number
+	if err := d.Decode(&n); err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid point timestamp: %s", err)
+	}
+
+	t, err := parseDecimalTime(n.String())
+	if err != nil {
+		return time.Time{}, "", err
+	}
+
+	var v string
+	if err := json.Unmarshal(pair[1], &v); err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid type of value, expected string: %s", err)
+	}
+
+	return t, v, nil
+}
+
+// parseDecimalTime parses seconds since the epoch, e.g. "1435781451.781".
+// Unlike parsing a float64, it does not lose any precision.
+func parseDecimalTime(s string) (time.Time, error) {
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("couldn't parse time %s: %s", s, err)
+		}
+
+		return time.Unix(0, int64(f*1e9)), nil
+	}
+
+	secs, frac, _ := strings.Cut(s, ".")
+	sec, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("couldn't parse time %s: %s", s, err)
+	}
+
+	if len(frac) > 9 {
+		frac = frac[:9]
+	}
+
+	var nsec int64
+	if frac != "" {
+		nsec, err = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("couldn't parse time %s: %s", s, err)
+		}
+	}
+
+	if strings.HasPrefix(secs, "-") {
+		nsec = -nsec
+	}
+
+	return time.Unix(sec, nsec), nil
+}
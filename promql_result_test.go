@@ -0,0 +1,134 @@
+package client_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	client "code.cloudfoundry.org/go-log-cache/v3"
+)
+
+func TestPromQLQueryResultAsVector(t *testing.T) {
+	t.Parallel()
+
+	r := decodePromQLQueryResult(t, `{
+		"status": "success",
+		"data": {
+			"resultType": "vector",
+			"result": [
+				{"metric": {"__name__": "cpu"}, "value": [1435781451.781, "1.5"]},
+				{"metric": {"__name__": "mem"}, "value": [1435781451.781, "NaN"]},
+				{"metric": {"__name__": "disk"}, "value": [1435781451.781, "+Inf"]},
+				{"metric": {"__name__": "net"}, "value": [1435781451.781, "-Inf"]}
+			]
+		},
+		"warnings": ["some-warning"]
+	}`)
+
+	samples, err := r.AsVector()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(samples) != 4 {
+		t.Fatalf("expected 4 samples: %d", len(samples))
+	}
+
+	if samples[0].Metric["__name__"] != "cpu" || samples[0].Point.Value != 1.5 {
+		t.Fatalf("wrong sample: %+v", samples[0])
+	}
+
+	if !samples[0].Point.Time.Equal(time.Unix(1435781451, 781000000)) {
+		t.Fatalf("wrong time: %s", samples[0].Point.Time)
+	}
+
+	if !math.IsNaN(samples[1].Point.Value) || !math.IsInf(samples[2].Point.Value, 1) || !math.IsInf(samples[3].Point.Value, -1) {
+		t.Fatalf("expected special values: %+v", samples[1:])
+	}
+
+	if len(r.Warnings) != 1 || r.Warnings[0] != "some-warning" {
+		t.Fatalf("expected warnings: %v", r.Warnings)
+	}
+}
+
+func TestPromQLQueryResultAsMatrix(t *testing.T) {
+	t.Parallel()
+
+	r := decodePromQLQueryResult(t, `{
+		"status": "success",
+		"data": {
+			"resultType": "matrix",
+			"result": [
+				{"metric": {"__name__": "cpu"}, "values": [[1, "1"], [2, "2"]]}
+			]
+		}
+	}`)
+
+	series, err := r.AsMatrix()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(series) != 1 || len(series[0].Points) != 2 {
+		t.Fatalf("wrong series: %+v", series)
+	}
+
+	if !series[0].Points[1].Time.Equal(time.Unix(2, 0)) || series[0].Points[1].Value != 2 {
+		t.Fatalf("wrong point: %+v", series[0].Points[1])
+	}
+}
+
+func TestPromQLQueryResultAsScalarAndString(t *testing.T) {
+	t.Parallel()
+
+	r := decodePromQLQueryResult(t, `{
+		"status": "success",
+		"data": {"resultType": "scalar", "result": [99.123456789, "101"]}
+	}`)
+
+	p, err := r.AsScalar()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !p.Time.Equal(time.Unix(99, 123456789)) || p.Value != 101 {
+		t.Fatalf("wrong point: %+v", p)
+	}
+
+	r = decodePromQLQueryResult(t, `{
+		"status": "success",
+		"data": {"resultType": "string", "result": [99, "some-string"]}
+	}`)
+
+	s, err := r.AsString()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !s.Time.Equal(time.Unix(99, 0)) || s.Value != "some-string" {
+		t.Fatalf("wrong string: %+v", s)
+	}
+}
+
+func TestPromQLQueryResultReturnsErrorForWrongResultType(t *testing.T) {
+	t.Parallel()
+
+	r := decodePromQLQueryResult(t, `{
+		"status": "success",
+		"data": {"resultType": "scalar", "result": [99, "101"]}
+	}`)
+
+	if _, err := r.AsVector(); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func decodePromQLQueryResult(t *testing.T, data string) *client.PromQLQueryResult {
+	var r client.PromQLQueryResult
+	if err := json.Unmarshal([]byte(data), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	return &r
+}
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
+	"golang.org/x/sync/errgroup"
+)
+
+// PromQLRangeSplit issues a PromQL range query like PromQLRange, but splits
+// it into step-aligned sub-ranges that do not exceed the maximum number of
+// points per query. The sub-ranges are queried concurrently and the series
+// of their results are stitched back together by their label sets. It
+// returns the first error of any sub-range.
+func (c *Client) PromQLRangeSplit(
+	ctx context.Context,
+	query string,
+	start time.Time,
+	end time.Time,
+	step time.Duration,
+	opts ...PromQLSplitOption,
+) (*logcache_v1.PromQL_RangeQueryResult, error) {
+	cfg := PromQLSplitConfig{
+		Parallelism: 4,
+		MaxPoints:   1000,
+	}
+
+	for _, o := range opts {
+		o(&cfg)
+	}
+
+	if step <= 0 {
+		return nil, errors.New("step must be positive")
+	}
+
+	if end.Before(start) {
+		return nil, errors.New("end must not be before start")
+	}
+
+	ranges := splitRange(start, end, step, max(cfg.MaxPoints, 1))
+	results := make([]*logcache_v1.PromQL_RangeQueryResult, len(ranges))
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(cfg.Parallelism, 1))
+
+	for i, r := range ranges {
+		g.Go(func() error {
+			result, err := c.PromQLRange(ctx, query,
+				WithPromQLStart(r[0]),
+				WithPromQLEnd(r[1]),
+				WithPromQLStep(strconv.FormatFloat(step.Seconds(), 'f', -1, 64)),
+			)
+			if err != nil {
+				return err
+			}
+
+			results[i] = result
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return stitchRangeQueryResults(results), nil
+}
+
+// PromQLSplitOption configures PromQLRangeSplit.
+type PromQLSplitOption func(*PromQLSplitConfig)
+
+// PromQLSplitConfig is the configuration of PromQLRangeSplit.
+type PromQLSplitConfig struct {
+	Parallelism int
+	MaxPoints   int
+}
+
+// WithPromQLSplitParallelism sets the maximum number of sub-ranges that are
+// queried concurrently. It defaults to 4.
+func WithPromQLSplitParallelism(n int) PromQLSplitOption {
+	return func(c *PromQLSplitConfig) {
+		c.Parallelism = n
+	}
+}
+
+// WithPromQLSplitMaxPoints sets the maximum number of points per series of
+// a sub-range. It defaults to 1000.
+func WithPromQLSplitMaxPoints(n int) PromQLSplitOption {
+	return func(c *PromQLSplitConfig) {
+		c.MaxPoints = n
+	}
+}
+
+// splitRange splits [start, end] into sub-ranges of at most maxPoints
+// steps. Each sub-range starts at a step of the whole range, so the points
+// of the sub-ranges are the points of the whole range.
+func splitRange(start, end time.Time, step time.Duration, maxPoints int) [][2]time.Time {
+	points := int64(end.Sub(start)/step) + 1
+
+	var ranges [][2]time.Time
+	for first := int64(0); first < points; first += int64(maxPoints) {
+		last := min(first+int64(maxPoints), points) - 1
+		ranges = append(ranges, [2]time.Time{
+			start.Add(time.Duration(first) * step),
+			start.Add(time.Duration(last) * step),
+		})
+	}
+
+	return ranges
+}
+
+// stitchRangeQueryResults merges the series of the results by their label
+// sets. Points with the same timestamp, e.g. at the boundaries of the
+// sub-ranges, are only kept once.
+func stitchRangeQueryResults(results []*logcache_v1.PromQL_RangeQueryResult) *logcache_v1.PromQL_RangeQueryResult {
+	var (
+		series   []*logcache_v1.PromQL_Series
+		byLabels = make(map[string]*logcache_v1.PromQL_Series)
+		warnings []string
+	)
+
+	for _, r := range results {
+		for _, w := range r.GetWarnings() {
+			if !slices.Contains(warnings, w) {
+				warnings = append(warnings, w)
+			}
+		}
+
+		for _, s := range r.GetMatrix().GetSeries() {
+			key := labelSetKey(s.GetMetric())
+			merged, ok := byLabels[key]
+			if !ok {
+				merged = &logcache_v1.PromQL_Series{Metric: s.GetMetric()}
+				byLabels[key] = merged
+				series = append(series, merged)
+			}
+
+			merged.Points = append(merged.Points, s.GetPoints()...)
+		}
+	}
+
+	for _, s := range series {
+		slices.SortStableFunc(s.Points, func(a, b *logcache_v1.PromQL_Point) int {
+			return compareTimestamps(a.GetTime(), b.GetTime())
+		})
+
+		s.Points = slices.CompactFunc(s.Points, func(a, b *logcache_v1.PromQL_Point) bool {
+			return compareTimestamps(a.GetTime(), b.GetTime()) == 0
+		})
+	}
+
+	return &logcache_v1.PromQL_RangeQueryResult{
+		Result: &logcache_v1.PromQL_RangeQueryResult_Matrix{
+			Matrix: &logcache_v1.PromQL_Matrix{
+				Series: series,
+			},
+		},
+		Warnings: warnings,
+	}
+}
+
+// labelSetKey returns a key that is unique for the label set.
+func labelSetKey(metric map[string]string) string {
+	keys := make([]string, 0, len(metric))
+	for k := range metric {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte(0xff)
+		b.WriteString(metric[k])
+		b.WriteByte(0xff)
+	}
+
+	return b.String()
+}
+
+// compareTimestamps compares two timestamps of points, e.g. "1.500".
+func compareTimestamps(a, b string) int {
+	ta, errA := parseDecimalTime(a)
+	tb, errB := parseDecimalTime(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+
+	return ta.Compare(tb)
+}
@@ -0,0 +1,139 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	client "code.cloudfoundry.org/go-log-cache/v3"
+)
+
+func TestPromQLRangeSplit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		requests int64
+		active   int64
+		mu       sync.Mutex
+		maxSeen  int64
+	)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		n := atomic.AddInt64(&active, 1)
+		defer atomic.AddInt64(&active, -1)
+
+		mu.Lock()
+		maxSeen = max(maxSeen, n)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+
+		start, _ := strconv.ParseFloat(r.URL.Query().Get("start"), 64)
+		end, _ := strconv.ParseFloat(r.URL.Query().Get("end"), 64)
+		step, _ := strconv.ParseFloat(r.URL.Query().Get("step"), 64)
+
+		// Only the second half has a second series.
+		withSecond := start >= 50
+
+		// Include the point before the sub-range to overlap with the
+		// previous one.
+		if start > 0 {
+			start -= step
+		}
+
+		var points []string
+		for ts := start; ts <= end; ts += step {
+			points = append(points, fmt.Sprintf(`[%g, "%g"]`, ts, ts))
+		}
+
+		series := []string{fmt.Sprintf(`{"metric":{"a":"1","b":"2"},"values":[%s]}`, strings.Join(points, ","))}
+		if withSecond {
+			series = append(series, fmt.Sprintf(`{"metric":{"a":"2"},"values":[%s]}`, strings.Join(points, ",")))
+		}
+
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"matrix","result":[%s]}}`, strings.Join(series, ","))
+	}))
+	defer s.Close()
+
+	c := client.NewClient(s.URL)
+	result, err := c.PromQLRangeSplit(
+		context.Background(),
+		"some-query",
+		time.Unix(0, 0),
+		time.Unix(99, 0),
+		time.Second,
+		client.WithPromQLSplitMaxPoints(10),
+		client.WithPromQLSplitParallelism(3),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if requests != 10 {
+		t.Fatalf("expected 10 requests: %d", requests)
+	}
+
+	if maxSeen > 3 {
+		t.Fatalf("expected at most 3 concurrent requests: %d", maxSeen)
+	}
+
+	series := result.GetMatrix().GetSeries()
+	if len(series) != 2 {
+		t.Fatalf("expected 2 series: %d", len(series))
+	}
+
+	if series[0].GetMetric()["b"] != "2" {
+		t.Fatalf("expected the series in the order they appear: %v", series[0].GetMetric())
+	}
+
+	points := series[0].GetPoints()
+	if len(points) != 100 {
+		t.Fatalf("expected 100 points: %d", len(points))
+	}
+
+	for i, p := range points {
+		if p.GetValue() != float64(i) {
+			t.Fatalf("expected points in order without duplicates: %v at %d", p, i)
+		}
+	}
+
+	if len(series[1].GetPoints()) != 51 || series[1].GetPoints()[0].GetValue() != 49 {
+		t.Fatalf("wrong points: %v", series[1].GetPoints())
+	}
+}
+
+func TestPromQLRangeSplitReturnsError(t *testing.T) {
+	t.Parallel()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"some-error"}`)) //nolint:errcheck
+	}))
+	defer s.Close()
+
+	c := client.NewClient(s.URL)
+	_, err := c.PromQLRangeSplit(context.Background(), "some-query", time.Unix(0, 0), time.Unix(99, 0), time.Second)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestPromQLRangeSplitRejectsInvalidRanges(t *testing.T) {
+	t.Parallel()
+
+	c := client.NewClient("http://some-addr")
+	if _, err := c.PromQLRangeSplit(context.Background(), "some-query", time.Unix(0, 0), time.Unix(99, 0), 0); err == nil {
+		t.Fatal("expected an error for a zero step")
+	}
+
+	if _, err := c.PromQLRangeSplit(context.Background(), "some-query", time.Unix(99, 0), time.Unix(0, 0), time.Second); err == nil {
+		t.Fatal("expected an error for an end before the start")
+	}
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+)
+
+// ReadLatest returns the newest n envelopes of the given source in
+// ascending order. It pages through the LogCache when n exceeds the limit
+// of a single read. The options are applied to each read, e.g.
+// WithEnvelopeTypes to only return certain envelopes or WithEndTime to
+// return the envelopes before the given time. WithLimit and WithDescending
+// are ignored.
+func (c *Client) ReadLatest(
+	ctx context.Context,
+	sourceID string,
+	n int,
+	opts ...ReadOption,
+) ([]*loggregator_v2.Envelope, error) {
+	return ReadLatest(ctx, sourceID, n, c.Read, opts...)
+}
+
+// ReadLatest returns the newest n envelopes of the given source from the
+// Reader in ascending order. See Client.ReadLatest.
+func ReadLatest(
+	ctx context.Context,
+	sourceID string,
+	n int,
+	r Reader,
+	opts ...ReadOption,
+) ([]*loggregator_v2.Envelope, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	req := &logcache_v1.ReadRequest{}
+	for _, o := range opts {
+		o(req)
+	}
+
+	walkOpts := []WalkOption{
+		WithWalkLimit(min(n, maxReadLimit)),
+		WithWalkMaxEnvelopes(n),
+	}
+
+	if req.GetEndTime() != 0 {
+		walkOpts = append(walkOpts, WithWalkEndTime(time.Unix(0, req.GetEndTime())))
+	}
+
+	// WalkDescending only logs errors, so they are captured here.
+	var readErr error
+	reader := func(ctx context.Context, sourceID string, start time.Time, o ...ReadOption) ([]*loggregator_v2.Envelope, error) {
+		es, err := r(ctx, sourceID, start, append(opts[:len(opts):len(opts)], o...)...)
+		readErr = err
+		return es, err
+	}
+
+	var results []*loggregator_v2.Envelope
+	WalkDescending(ctx, sourceID, func(es []*loggregator_v2.Envelope) bool {
+		results = append(results, es...)
+		return true
+	}, reader, walkOpts...)
+
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	slices.Reverse(results)
+	return results, nil
+}
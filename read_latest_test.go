@@ -0,0 +1,101 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	client "code.cloudfoundry.org/go-log-cache/v3"
+	"code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+)
+
+func TestReadLatest(t *testing.T) {
+	t.Parallel()
+
+	var store []*loggregator_v2.Envelope
+	for i := 1; i <= 2500; i++ {
+		store = append(store, &loggregator_v2.Envelope{Timestamp: int64(i)})
+	}
+
+	r := newLimitReader(store)
+	es, err := client.ReadLatest(context.Background(), "some-id", 1500, r.read)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(es) != 1500 {
+		t.Fatalf("expected 1500 envelopes: %d", len(es))
+	}
+
+	for i, e := range es {
+		if e.Timestamp != int64(1001+i) {
+			t.Fatalf("expected the newest envelopes in ascending order: %d at %d", e.Timestamp, i)
+		}
+	}
+}
+
+func TestReadLatestHonorsEndTime(t *testing.T) {
+	t.Parallel()
+
+	var store []*loggregator_v2.Envelope
+	for i := 1; i <= 50; i++ {
+		store = append(store, &loggregator_v2.Envelope{Timestamp: int64(i)})
+	}
+
+	r := newLimitReader(store)
+	es, err := client.ReadLatest(context.Background(), "some-id", 5, r.read,
+		client.WithEndTime(time.Unix(0, 21)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(es) != 5 || es[0].Timestamp != 16 || es[4].Timestamp != 20 {
+		t.Fatalf("wrong envelopes: %v", es)
+	}
+}
+
+func TestReadLatestPassesOptions(t *testing.T) {
+	t.Parallel()
+
+	r := &stubReader{
+		envelopes: [][]*loggregator_v2.Envelope{{{Timestamp: 1}}},
+		errs:      []error{nil},
+	}
+
+	_, err := client.ReadLatest(context.Background(), "some-id", 10, r.read,
+		client.WithEnvelopeTypes(logcache_v1.EnvelopeType_LOG),
+		client.WithLimit(1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &logcache_v1.ReadRequest{}
+	for _, o := range r.opts[0] {
+		o(req)
+	}
+
+	if len(req.EnvelopeTypes) != 1 || req.EnvelopeTypes[0] != logcache_v1.EnvelopeType_LOG {
+		t.Fatalf("expected envelope types to equal LOG: %v", req.EnvelopeTypes)
+	}
+
+	if !req.Descending || req.Limit != 10 {
+		t.Fatalf("expected a descending read of 10 envelopes: %v", req)
+	}
+}
+
+func TestReadLatestReturnsError(t *testing.T) {
+	t.Parallel()
+
+	r := &stubReader{
+		envelopes: [][]*loggregator_v2.Envelope{nil},
+		errs:      []error{errors.New("some-error")},
+	}
+
+	if _, err := client.ReadLatest(context.Background(), "some-id", 10, r.read); err == nil {
+		t.Fatal("expected an error")
+	}
+}
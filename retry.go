@@ -0,0 +1,268 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// RetryPolicy determines if and when a failed Client call is retried. It
+// applies to both the HTTP and gRPC transport. Zero values fall back to the
+// values of DefaultRetryPolicy, except for Jitter and Budget.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first
+	// one.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. Each following retry
+	// waits Multiplier times longer than the previous one, up to MaxDelay.
+	BaseDelay  time.Duration
+	Multiplier float64
+	MaxDelay   time.Duration
+
+	// Jitter randomizes each delay by up to the given fraction, e.g. 0.2
+	// results in delays between 80% and 120% of the computed delay.
+	Jitter float64
+
+	// RetryableStatusCodes are the HTTP status codes that are retried.
+	// Errors from the gRPC transport are mapped onto HTTP status codes (see
+	// StatusError).
+	RetryableStatusCodes []int
+
+	// RetryableCodes are the gRPC codes that are retried. Errors from the
+	// HTTP transport are mapped onto gRPC codes (see StatusError).
+	RetryableCodes []codes.Code
+
+	// Retryable overrides the classification via status codes if set.
+	Retryable func(err error) bool
+
+	// Budget limits the retries of all calls made by the Client. It defaults
+	// to no limit.
+	Budget *RetryBudget
+}
+
+// DefaultRetryPolicy returns a RetryPolicy that retries rate limiting,
+// unavailable servers and failed connections up to 3 times.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   100 * time.Millisecond,
+		Multiplier:  2,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableCodes: []codes.Code{
+			codes.Unavailable,
+			codes.ResourceExhausted,
+		},
+	}
+}
+
+// WithRetryPolicy sets the RetryPolicy for every call of the Client. It
+// defaults to not retrying.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return clientOptionFunc(func(c interface{}) {
+		switch c := c.(type) {
+		case *Client:
+			p := p.withDefaults()
+			c.retryPolicy = &p
+		default:
+			panic("unknown type")
+		}
+	})
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	d := DefaultRetryPolicy()
+
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = d.MaxAttempts
+	}
+
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = d.BaseDelay
+	}
+
+	if p.Multiplier <= 0 {
+		p.Multiplier = d.Multiplier
+	}
+
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = d.MaxDelay
+	}
+
+	if p.RetryableStatusCodes == nil {
+		p.RetryableStatusCodes = d.RetryableStatusCodes
+	}
+
+	if p.RetryableCodes == nil {
+		p.RetryableCodes = d.RetryableCodes
+	}
+
+	return p
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return slices.Contains(p.RetryableStatusCodes, statusErr.StatusCode) ||
+			slices.Contains(p.RetryableCodes, statusErr.Code)
+	}
+
+	// The request did not get a response, e.g. the connection was refused
+	// while LogCache restarts.
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// delay returns how long to wait after the given (1-based) attempt failed
+// with the given error.
+func (p *RetryPolicy) delay(attempt int, err error) time.Duration {
+	d := exponentialDelay(attempt-1, p.BaseDelay, p.Multiplier, p.MaxDelay, p.Jitter)
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > d {
+		d = statusErr.RetryAfter
+	}
+
+	return d
+}
+
+// withRetry invokes f until it succeeds or the policy gives up. A nil policy
+// invokes f once.
+func withRetry[T any](ctx context.Context, p *RetryPolicy, f func() (T, error)) (T, error) {
+	if p == nil {
+		return f()
+	}
+
+	for attempt := 1; ; attempt++ {
+		v, err := f()
+		if err == nil {
+			p.Budget.onSuccess()
+			return v, nil
+		}
+
+		if ctx.Err() != nil || !p.retryable(err) {
+			return v, err
+		}
+
+		p.Budget.onFailure()
+		if attempt >= p.MaxAttempts || !p.Budget.allow() {
+			return v, err
+		}
+
+		d := p.delay(attempt, err)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(d).After(deadline) {
+			// Waiting would exceed the deadline anyway.
+			return v, err
+		}
+
+		if !sleepContext(ctx, d) {
+			return v, err
+		}
+	}
+}
+
+// RetryBudget limits retries across calls so that a struggling LogCache is
+// not overwhelmed by them. It follows the retry throttling of gRPC: every
+// failed attempt removes a token, every successful call adds tokenRatio
+// tokens and retries are only allowed while more than half of the tokens
+// are left.
+type RetryBudget struct {
+	mu         sync.Mutex
+	maxTokens  float64
+	tokenRatio float64
+	tokens     float64
+}
+
+// NewRetryBudget returns a new RetryBudget.
+func NewRetryBudget(maxTokens int, tokenRatio float64) *RetryBudget {
+	return &RetryBudget{
+		maxTokens:  float64(maxTokens),
+		tokenRatio: tokenRatio,
+		tokens:     float64(maxTokens),
+	}
+}
+
+func (b *RetryBudget) allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.tokens > b.maxTokens/2
+}
+
+func (b *RetryBudget) onFailure() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Max(0, b.tokens-1)
+}
+
+func (b *RetryBudget) onSuccess() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.maxTokens, b.tokens+b.tokenRatio)
+}
+
+// exponentialDelay returns base*multiplier^n capped at maxDelay and
+// randomized by the given jitter fraction.
+func exponentialDelay(n int, base time.Duration, multiplier float64, maxDelay time.Duration, jitter float64) time.Duration {
+	d := float64(base) * math.Pow(multiplier, float64(n))
+	if maxDelay > 0 && d > float64(maxDelay) {
+		d = float64(maxDelay)
+	}
+
+	if jitter > 0 {
+		d *= 1 + jitter*(2*rand.Float64()-1) //nolint:gosec
+	}
+
+	return time.Duration(d)
+}
+
+// sleepContext waits for the given duration. It returns false if the context
+// is done before.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,278 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	client "code.cloudfoundry.org/go-log-cache/v3"
+
+	rpc "code.cloudfoundry.org/go-log-cache/v3/rpc/logcache_v1"
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryPolicyRetriesUnavailable(t *testing.T) {
+	t.Parallel()
+
+	s := newFlakyLogCache(t, http.StatusServiceUnavailable, 2)
+	c := client.NewClient(s.URL, client.WithRetryPolicy(client.RetryPolicy{
+		BaseDelay: time.Millisecond,
+	}))
+
+	meta, err := c.Meta(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(meta) != 1 {
+		t.Fatalf("expected meta to be returned: %v", meta)
+	}
+
+	if s.attempts() != 3 {
+		t.Fatalf("expected 3 attempts: %d", s.attempts())
+	}
+}
+
+func TestRetryPolicyGivesUpAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	s := newFlakyLogCache(t, http.StatusBadGateway, 10)
+	c := client.NewClient(s.URL, client.WithRetryPolicy(client.RetryPolicy{
+		MaxAttempts: 2,
+		BaseDelay:   time.Millisecond,
+	}))
+
+	_, err := c.Meta(context.Background())
+
+	var statusErr *client.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected a StatusError with status code 502: %v", err)
+	}
+
+	if s.attempts() != 2 {
+		t.Fatalf("expected 2 attempts: %d", s.attempts())
+	}
+}
+
+func TestRetryPolicyDoesNotRetryOtherStatusCodes(t *testing.T) {
+	t.Parallel()
+
+	s := newFlakyLogCache(t, http.StatusForbidden, 1)
+	c := client.NewClient(s.URL, client.WithRetryPolicy(client.RetryPolicy{
+		BaseDelay: time.Millisecond,
+	}))
+
+	_, err := c.Meta(context.Background())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if s.attempts() != 1 {
+		t.Fatalf("expected 1 attempt: %d", s.attempts())
+	}
+}
+
+func TestRetryPolicyHonorsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	s := newFlakyLogCache(t, http.StatusTooManyRequests, 1)
+	s.mu.Lock()
+	s.retryAfter = "1"
+	s.mu.Unlock()
+	c := client.NewClient(s.URL, client.WithRetryPolicy(client.RetryPolicy{
+		BaseDelay: time.Millisecond,
+	}))
+
+	start := time.Now()
+	_, err := c.Meta(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if time.Since(start) < time.Second {
+		t.Fatalf("expected to wait for Retry-After: %s", time.Since(start))
+	}
+}
+
+func TestRetryPolicyStopsWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	s := newFlakyLogCache(t, http.StatusServiceUnavailable, 10)
+	c := client.NewClient(s.URL, client.WithRetryPolicy(client.RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   time.Hour,
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.Meta(ctx)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("expected to give up before the deadline: %s", time.Since(start))
+	}
+}
+
+func TestRetryPolicyHonorsBudget(t *testing.T) {
+	t.Parallel()
+
+	s := newFlakyLogCache(t, http.StatusServiceUnavailable, 100)
+	c := client.NewClient(s.URL, client.WithRetryPolicy(client.RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   time.Millisecond,
+		Budget:      client.NewRetryBudget(4, 0.1),
+	}))
+
+	for i := 0; i < 3; i++ {
+		_, err := c.Meta(context.Background())
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+
+	// Only the first call is retried until half of the budget is spent.
+	if s.attempts() != 4 {
+		t.Fatalf("expected 4 attempts: %d", s.attempts())
+	}
+}
+
+func TestRetryPolicyRetriesGRPCUnavailable(t *testing.T) {
+	t.Parallel()
+
+	s := newFlakyGrpcLogCache(t, codes.Unavailable, 2)
+	c := client.NewClient(s.addr(),
+		client.WithViaGRPC(insecureOpt),
+		client.WithRetryPolicy(client.RetryPolicy{
+			BaseDelay: time.Millisecond,
+		}),
+	)
+
+	es, err := c.Read(context.Background(), "some-id", time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(es) != 1 {
+		t.Fatalf("expected envelopes to be returned: %v", es)
+	}
+
+	if s.attempts() != 3 {
+		t.Fatalf("expected 3 attempts: %d", s.attempts())
+	}
+}
+
+type flakyLogCache struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	statusCode int
+	failures   int
+	retryAfter string
+	count      int
+}
+
+// newFlakyLogCache returns a LogCache that fails the given number of meta
+// requests with the given status code before it succeeds.
+func newFlakyLogCache(t *testing.T, statusCode, failures int) *flakyLogCache {
+	s := &flakyLogCache{
+		statusCode: statusCode,
+		failures:   failures,
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/info" {
+			w.Write([]byte(`{"version":"2.0.0"}`)) //nolint:errcheck
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.count++
+
+		if s.count <= s.failures {
+			if s.retryAfter != "" {
+				w.Header().Set("Retry-After", s.retryAfter)
+			}
+			w.WriteHeader(s.statusCode)
+			return
+		}
+
+		w.Write([]byte(`{"meta":{"source-0":{}}}`)) //nolint:errcheck
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *flakyLogCache) attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+type flakyGrpcLogCache struct {
+	mu       sync.Mutex
+	code     codes.Code
+	failures int
+	count    int
+	lis      net.Listener
+	rpc.UnimplementedEgressServer
+}
+
+func newFlakyGrpcLogCache(t *testing.T, code codes.Code, failures int) *flakyGrpcLogCache {
+	s := &flakyGrpcLogCache{
+		code:     code,
+		failures: failures,
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.lis = lis
+	srv := grpc.NewServer()
+	rpc.RegisterEgressServer(srv, s)
+	go srv.Serve(lis) //nolint:errcheck
+	t.Cleanup(srv.Stop)
+
+	return s
+}
+
+func (s *flakyGrpcLogCache) addr() string {
+	return s.lis.Addr().String()
+}
+
+func (s *flakyGrpcLogCache) Read(ctx context.Context, r *rpc.ReadRequest) (*rpc.ReadResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count++
+
+	if s.count <= s.failures {
+		return nil, status.Error(s.code, "some-error")
+	}
+
+	return &rpc.ReadResponse{
+		Envelopes: &loggregator_v2.EnvelopeBatch{
+			Batch: []*loggregator_v2.Envelope{{Timestamp: 1}},
+		},
+	}, nil
+}
+
+func (s *flakyGrpcLogCache) attempts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
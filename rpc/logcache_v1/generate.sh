@@ -2,11 +2,23 @@
 
 # This script re-generates the Go code in this directory from the `.proto` files
 # in the `api`. [protoc](https://github.com/protocolbuffers/protobuf/releases)
-# must be installed beforehand.
+# must be installed beforehand, in the version below.
 # Usage: `rpc/logcache_v1/generate.sh`.
 
 set -euxo pipefail
 
+# The versions of the toolchain are pinned, so that all generated files are
+# generated alike.
+PROTOC_VERSION="27.1"
+PROTOC_GEN_GO_VERSION="v1.34.2"
+PROTOC_GEN_GO_GRPC_VERSION="v1.4.0"
+PROTOC_GEN_GRPC_GATEWAY_VERSION="v2.20.0"
+
+if [[ "$(protoc --version)" != "libprotoc ${PROTOC_VERSION}" ]]; then
+  echo "protoc ${PROTOC_VERSION} is required, found $(protoc --version)" >&2
+  exit 1
+fi
+
 REPO_ROOT="$(cd "$(dirname "${BASH_SOURCE[0]}")/../.." && pwd -P)"
 
 TMP_DIR=$(mktemp -d)
@@ -18,9 +30,9 @@ git clone --depth 1 "https://github.com/cloudfoundry/loggregator-api" "${TMP_DIR
 
 export GOBIN="${TMP_DIR}/hack/bin"
 export PATH="${GOBIN}:${PATH}"
-go install google.golang.org/protobuf/cmd/protoc-gen-go@latest
-go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@latest
-go install github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-grpc-gateway@latest
+go install "google.golang.org/protobuf/cmd/protoc-gen-go@${PROTOC_GEN_GO_VERSION}"
+go install "google.golang.org/grpc/cmd/protoc-gen-go-grpc@${PROTOC_GEN_GO_GRPC_VERSION}"
+go install "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-grpc-gateway@${PROTOC_GEN_GRPC_GATEWAY_VERSION}"
 
 pushd "${REPO_ROOT}/.." > /dev/null
   mkdir -p "${TMP_DIR}/go-log-cache/api"
@@ -191,11 +191,15 @@ type PromQL_InstantQueryResult struct {
 	unknownFields protoimpl.UnknownFields
 
 	// Types that are assignable to Result:
-	//
 	//	*PromQL_InstantQueryResult_Scalar
 	//	*PromQL_InstantQueryResult_Vector
 	//	*PromQL_InstantQueryResult_Matrix
-	Result isPromQL_InstantQueryResult_Result `protobuf_oneof:"Result"`
+	//	*PromQL_InstantQueryResult_StringValue
+	Result    isPromQL_InstantQueryResult_Result `protobuf_oneof:"Result"`
+	Status    string                             `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	ErrorType string                             `protobuf:"bytes,6,opt,name=error_type,json=errorType,proto3" json:"error_type,omitempty"`
+	Error     string                             `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	Warnings  []string                           `protobuf:"bytes,8,rep,name=warnings,proto3" json:"warnings,omitempty"`
 }
 
 func (x *PromQL_InstantQueryResult) Reset() {
@@ -258,6 +262,41 @@ func (x *PromQL_InstantQueryResult) GetMatrix() *PromQL_Matrix {
 	return nil
 }
 
+func (x *PromQL_InstantQueryResult) GetStringValue() *PromQL_String {
+	if x, ok := x.GetResult().(*PromQL_InstantQueryResult_StringValue); ok {
+		return x.StringValue
+	}
+	return nil
+}
+
+func (x *PromQL_InstantQueryResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PromQL_InstantQueryResult) GetErrorType() string {
+	if x != nil {
+		return x.ErrorType
+	}
+	return ""
+}
+
+func (x *PromQL_InstantQueryResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PromQL_InstantQueryResult) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
 type isPromQL_InstantQueryResult_Result interface {
 	isPromQL_InstantQueryResult_Result()
 }
@@ -274,21 +313,30 @@ type PromQL_InstantQueryResult_Matrix struct {
 	Matrix *PromQL_Matrix `protobuf:"bytes,3,opt,name=matrix,proto3,oneof"`
 }
 
+type PromQL_InstantQueryResult_StringValue struct {
+	StringValue *PromQL_String `protobuf:"bytes,4,opt,name=string_value,json=stringValue,proto3,oneof"`
+}
+
 func (*PromQL_InstantQueryResult_Scalar) isPromQL_InstantQueryResult_Result() {}
 
 func (*PromQL_InstantQueryResult_Vector) isPromQL_InstantQueryResult_Result() {}
 
 func (*PromQL_InstantQueryResult_Matrix) isPromQL_InstantQueryResult_Result() {}
 
+func (*PromQL_InstantQueryResult_StringValue) isPromQL_InstantQueryResult_Result() {}
+
 type PromQL_RangeQueryResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	// Types that are assignable to Result:
-	//
 	//	*PromQL_RangeQueryResult_Matrix
-	Result isPromQL_RangeQueryResult_Result `protobuf_oneof:"Result"`
+	Result    isPromQL_RangeQueryResult_Result `protobuf_oneof:"Result"`
+	Status    string                           `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	ErrorType string                           `protobuf:"bytes,3,opt,name=error_type,json=errorType,proto3" json:"error_type,omitempty"`
+	Error     string                           `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Warnings  []string                         `protobuf:"bytes,5,rep,name=warnings,proto3" json:"warnings,omitempty"`
 }
 
 func (x *PromQL_RangeQueryResult) Reset() {
@@ -337,6 +385,34 @@ func (x *PromQL_RangeQueryResult) GetMatrix() *PromQL_Matrix {
 	return nil
 }
 
+func (x *PromQL_RangeQueryResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PromQL_RangeQueryResult) GetErrorType() string {
+	if x != nil {
+		return x.ErrorType
+	}
+	return ""
+}
+
+func (x *PromQL_RangeQueryResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PromQL_RangeQueryResult) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
 type isPromQL_RangeQueryResult_Result interface {
 	isPromQL_RangeQueryResult_Result()
 }
@@ -402,6 +478,61 @@ func (x *PromQL_Scalar) GetValue() float64 {
 	return 0
 }
 
+type PromQL_String struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Time  string `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *PromQL_String) Reset() {
+	*x = PromQL_String{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PromQL_String) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PromQL_String) ProtoMessage() {}
+
+func (x *PromQL_String) ProtoReflect() protoreflect.Message {
+	mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PromQL_String.ProtoReflect.Descriptor instead.
+func (*PromQL_String) Descriptor() ([]byte, []int) {
+	return file_go_log_cache_api_v1_promql_proto_rawDescGZIP(), []int{0, 5}
+}
+
+func (x *PromQL_String) GetTime() string {
+	if x != nil {
+		return x.Time
+	}
+	return ""
+}
+
+func (x *PromQL_String) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
 type PromQL_Vector struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -413,7 +544,7 @@ type PromQL_Vector struct {
 func (x *PromQL_Vector) Reset() {
 	*x = PromQL_Vector{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[6]
+		mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -426,7 +557,7 @@ func (x *PromQL_Vector) String() string {
 func (*PromQL_Vector) ProtoMessage() {}
 
 func (x *PromQL_Vector) ProtoReflect() protoreflect.Message {
-	mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[6]
+	mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -439,7 +570,7 @@ func (x *PromQL_Vector) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PromQL_Vector.ProtoReflect.Descriptor instead.
 func (*PromQL_Vector) Descriptor() ([]byte, []int) {
-	return file_go_log_cache_api_v1_promql_proto_rawDescGZIP(), []int{0, 5}
+	return file_go_log_cache_api_v1_promql_proto_rawDescGZIP(), []int{0, 6}
 }
 
 func (x *PromQL_Vector) GetSamples() []*PromQL_Sample {
@@ -461,7 +592,7 @@ type PromQL_Point struct {
 func (x *PromQL_Point) Reset() {
 	*x = PromQL_Point{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[7]
+		mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -474,7 +605,7 @@ func (x *PromQL_Point) String() string {
 func (*PromQL_Point) ProtoMessage() {}
 
 func (x *PromQL_Point) ProtoReflect() protoreflect.Message {
-	mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[7]
+	mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -487,7 +618,7 @@ func (x *PromQL_Point) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PromQL_Point.ProtoReflect.Descriptor instead.
 func (*PromQL_Point) Descriptor() ([]byte, []int) {
-	return file_go_log_cache_api_v1_promql_proto_rawDescGZIP(), []int{0, 6}
+	return file_go_log_cache_api_v1_promql_proto_rawDescGZIP(), []int{0, 7}
 }
 
 func (x *PromQL_Point) GetTime() string {
@@ -516,7 +647,7 @@ type PromQL_Sample struct {
 func (x *PromQL_Sample) Reset() {
 	*x = PromQL_Sample{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[8]
+		mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -529,7 +660,7 @@ func (x *PromQL_Sample) String() string {
 func (*PromQL_Sample) ProtoMessage() {}
 
 func (x *PromQL_Sample) ProtoReflect() protoreflect.Message {
-	mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[8]
+	mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -542,7 +673,7 @@ func (x *PromQL_Sample) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PromQL_Sample.ProtoReflect.Descriptor instead.
 func (*PromQL_Sample) Descriptor() ([]byte, []int) {
-	return file_go_log_cache_api_v1_promql_proto_rawDescGZIP(), []int{0, 7}
+	return file_go_log_cache_api_v1_promql_proto_rawDescGZIP(), []int{0, 8}
 }
 
 func (x *PromQL_Sample) GetMetric() map[string]string {
@@ -570,7 +701,7 @@ type PromQL_Matrix struct {
 func (x *PromQL_Matrix) Reset() {
 	*x = PromQL_Matrix{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[9]
+		mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -583,7 +714,7 @@ func (x *PromQL_Matrix) String() string {
 func (*PromQL_Matrix) ProtoMessage() {}
 
 func (x *PromQL_Matrix) ProtoReflect() protoreflect.Message {
-	mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[9]
+	mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -596,7 +727,7 @@ func (x *PromQL_Matrix) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PromQL_Matrix.ProtoReflect.Descriptor instead.
 func (*PromQL_Matrix) Descriptor() ([]byte, []int) {
-	return file_go_log_cache_api_v1_promql_proto_rawDescGZIP(), []int{0, 8}
+	return file_go_log_cache_api_v1_promql_proto_rawDescGZIP(), []int{0, 9}
 }
 
 func (x *PromQL_Matrix) GetSeries() []*PromQL_Series {
@@ -618,7 +749,7 @@ type PromQL_Series struct {
 func (x *PromQL_Series) Reset() {
 	*x = PromQL_Series{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[10]
+		mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -631,7 +762,7 @@ func (x *PromQL_Series) String() string {
 func (*PromQL_Series) ProtoMessage() {}
 
 func (x *PromQL_Series) ProtoReflect() protoreflect.Message {
-	mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[10]
+	mi := &file_go_log_cache_api_v1_promql_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -644,7 +775,7 @@ func (x *PromQL_Series) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PromQL_Series.ProtoReflect.Descriptor instead.
 func (*PromQL_Series) Descriptor() ([]byte, []int) {
-	return file_go_log_cache_api_v1_promql_proto_rawDescGZIP(), []int{0, 9}
+	return file_go_log_cache_api_v1_promql_proto_rawDescGZIP(), []int{0, 10}
 }
 
 func (x *PromQL_Series) GetMetric() map[string]string {
@@ -668,7 +799,7 @@ var file_go_log_cache_api_v1_promql_proto_rawDesc = []byte{
 	0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x72, 0x6f, 0x6d, 0x71, 0x6c, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x12, 0x0b, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x1a,
 	0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x6e, 0x6e, 0x6f,
-	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9c, 0x08,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xe4, 0x0a,
 	0x0a, 0x06, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x1a, 0x3f, 0x0a, 0x13, 0x49, 0x6e, 0x73, 0x74,
 	0x61, 0x6e, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
 	0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
@@ -680,7 +811,7 @@ var file_go_log_cache_api_v1_promql_proto_rawDesc = []byte{
 	0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e,
 	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04,
 	0x73, 0x74, 0x65, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x74, 0x65, 0x70,
-	0x1a, 0xc0, 0x01, 0x0a, 0x12, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x51, 0x75, 0x65, 0x72,
+	0x1a, 0xea, 0x02, 0x0a, 0x12, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x51, 0x75, 0x65, 0x72,
 	0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x34, 0x0a, 0x06, 0x73, 0x63, 0x61, 0x6c, 0x61,
 	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63,
 	0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x53, 0x63, 0x61,
@@ -691,70 +822,91 @@ var file_go_log_cache_api_v1_promql_proto_rawDesc = []byte{
 	0x74, 0x6f, 0x72, 0x12, 0x34, 0x0a, 0x06, 0x6d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x18, 0x03, 0x20,
 	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76,
 	0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x48,
-	0x00, 0x52, 0x06, 0x6d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x42, 0x08, 0x0a, 0x06, 0x52, 0x65, 0x73,
-	0x75, 0x6c, 0x74, 0x1a, 0x52, 0x0a, 0x10, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x51, 0x75, 0x65, 0x72,
-	0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x34, 0x0a, 0x06, 0x6d, 0x61, 0x74, 0x72, 0x69,
-	0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63,
-	0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x4d, 0x61, 0x74,
-	0x72, 0x69, 0x78, 0x48, 0x00, 0x52, 0x06, 0x6d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x42, 0x08, 0x0a,
-	0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x1a, 0x32, 0x0a, 0x06, 0x53, 0x63, 0x61, 0x6c, 0x61,
-	0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x1a, 0x3e, 0x0a, 0x06, 0x56,
-	0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x34, 0x0a, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68,
-	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x53, 0x61, 0x6d, 0x70,
-	0x6c, 0x65, 0x52, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x1a, 0x31, 0x0a, 0x05, 0x50,
-	0x6f, 0x69, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x1a, 0xb4,
-	0x01, 0x0a, 0x06, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x12, 0x3e, 0x0a, 0x06, 0x6d, 0x65, 0x74,
-	0x72, 0x69, 0x63, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6c, 0x6f, 0x67, 0x63,
-	0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x53,
-	0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x45, 0x6e, 0x74, 0x72,
-	0x79, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x2f, 0x0a, 0x05, 0x70, 0x6f, 0x69,
-	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61,
-	0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x50, 0x6f,
-	0x69, 0x6e, 0x74, 0x52, 0x05, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x1a, 0x39, 0x0a, 0x0b, 0x4d, 0x65,
-	0x74, 0x72, 0x69, 0x63, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3c, 0x0a, 0x06, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x12,
-	0x32, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x00, 0x52, 0x06, 0x6d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x12, 0x3f, 0x0a, 0x0c, 0x73, 0x74, 0x72,
+	0x69, 0x6e, 0x67, 0x5f, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
 	0x1a, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72,
-	0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x52, 0x06, 0x73, 0x65, 0x72,
-	0x69, 0x65, 0x73, 0x1a, 0xb6, 0x01, 0x0a, 0x06, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x12, 0x3e,
-	0x0a, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26,
-	0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f,
-	0x6d, 0x51, 0x4c, 0x2e, 0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69,
-	0x63, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x31,
-	0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19,
+	0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x48, 0x00, 0x52, 0x0b, 0x73,
+	0x74, 0x72, 0x69, 0x6e, 0x67, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x77, 0x61, 0x72, 0x6e, 0x69,
+	0x6e, 0x67, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x77, 0x61, 0x72, 0x6e, 0x69,
+	0x6e, 0x67, 0x73, 0x42, 0x08, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x1a, 0xbb, 0x01,
+	0x0a, 0x10, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x34, 0x0a, 0x06, 0x6d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x48, 0x00,
+	0x52, 0x06, 0x6d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x77, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x77, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67,
+	0x73, 0x42, 0x08, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x1a, 0x32, 0x0a, 0x06, 0x53,
+	0x63, 0x61, 0x6c, 0x61, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x1a,
+	0x32, 0x0a, 0x06, 0x53, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x1a, 0x3e, 0x0a, 0x06, 0x56, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x34, 0x0a,
+	0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a,
 	0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f,
-	0x6d, 0x51, 0x4c, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74,
-	0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x45, 0x6e, 0x74, 0x72, 0x79,
-	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
-	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x32, 0xff, 0x01, 0x0a,
-	0x0d, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x51, 0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x12, 0x76,
-	0x0a, 0x0c, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x27,
-	0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f,
-	0x6d, 0x51, 0x4c, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63,
-	0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x49, 0x6e, 0x73,
-	0x74, 0x61, 0x6e, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22,
-	0x15, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0f, 0x12, 0x0d, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31,
-	0x2f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x76, 0x0a, 0x0a, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x51,
-	0x75, 0x65, 0x72, 0x79, 0x12, 0x25, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e,
-	0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x51,
-	0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x6c, 0x6f,
-	0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c,
-	0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c,
-	0x74, 0x22, 0x1b, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x15, 0x12, 0x13, 0x2f, 0x61, 0x70, 0x69, 0x2f,
-	0x76, 0x31, 0x2f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x37,
-	0x5a, 0x35, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x66, 0x6f, 0x75, 0x6e,
-	0x64, 0x72, 0x79, 0x2e, 0x6f, 0x72, 0x67, 0x2f, 0x67, 0x6f, 0x2d, 0x6c, 0x6f, 0x67, 0x2d, 0x63,
-	0x61, 0x63, 0x68, 0x65, 0x2f, 0x76, 0x33, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x6c, 0x6f, 0x67, 0x63,
-	0x61, 0x63, 0x68, 0x65, 0x5f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6d, 0x51, 0x4c, 0x2e, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x52, 0x07, 0x73, 0x61, 0x6d, 0x70,
+	0x6c, 0x65, 0x73, 0x1a, 0x31, 0x0a, 0x05, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x1a, 0xb4, 0x01, 0x0a, 0x06, 0x53, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x12, 0x3e, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x26, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x2e, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x12, 0x2f, 0x0a, 0x05, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x05, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x1a, 0x39, 0x0a, 0x0b, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3c, 0x0a,
+	0x06, 0x4d, 0x61, 0x74, 0x72, 0x69, 0x78, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x69, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63,
+	0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x53, 0x65, 0x72,
+	0x69, 0x65, 0x73, 0x52, 0x06, 0x73, 0x65, 0x72, 0x69, 0x65, 0x73, 0x1a, 0xb6, 0x01, 0x0a, 0x06,
+	0x53, 0x65, 0x72, 0x69, 0x65, 0x73, 0x12, 0x3e, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x53, 0x65, 0x72, 0x69,
+	0x65, 0x73, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06,
+	0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x12, 0x31, 0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x50, 0x6f, 0x69, 0x6e,
+	0x74, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x4d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x32, 0xff, 0x01, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x51,
+	0x75, 0x65, 0x72, 0x69, 0x65, 0x72, 0x12, 0x76, 0x0a, 0x0c, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x27, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x49, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x74, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x26, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x74, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x15, 0x82, 0xd3, 0xe4, 0x93, 0x02, 0x0f, 0x12,
+	0x0d, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x76,
+	0x0a, 0x0a, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x25, 0x2e, 0x6c,
+	0x6f, 0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51,
+	0x4c, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x51, 0x75, 0x65, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x72, 0x6f, 0x6d, 0x51, 0x4c, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x51, 0x75,
+	0x65, 0x72, 0x79, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x1b, 0x82, 0xd3, 0xe4, 0x93, 0x02,
+	0x15, 0x12, 0x13, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x71, 0x75, 0x65, 0x72, 0x79,
+	0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x42, 0x37, 0x5a, 0x35, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x66, 0x6f, 0x75, 0x6e, 0x64, 0x72, 0x79, 0x2e, 0x6f, 0x72, 0x67, 0x2f,
+	0x67, 0x6f, 0x2d, 0x6c, 0x6f, 0x67, 0x2d, 0x63, 0x61, 0x63, 0x68, 0x65, 0x2f, 0x76, 0x33, 0x2f,
+	0x72, 0x70, 0x63, 0x2f, 0x6c, 0x6f, 0x67, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x76, 0x31, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -769,7 +921,7 @@ func file_go_log_cache_api_v1_promql_proto_rawDescGZIP() []byte {
 	return file_go_log_cache_api_v1_promql_proto_rawDescData
 }
 
-var file_go_log_cache_api_v1_promql_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_go_log_cache_api_v1_promql_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_go_log_cache_api_v1_promql_proto_goTypes = []any{
 	(*PromQL)(nil),                     // 0: logcache.v1.PromQL
 	(*PromQL_InstantQueryRequest)(nil), // 1: logcache.v1.PromQL.InstantQueryRequest
@@ -777,34 +929,36 @@ var file_go_log_cache_api_v1_promql_proto_goTypes = []any{
 	(*PromQL_InstantQueryResult)(nil),  // 3: logcache.v1.PromQL.InstantQueryResult
 	(*PromQL_RangeQueryResult)(nil),    // 4: logcache.v1.PromQL.RangeQueryResult
 	(*PromQL_Scalar)(nil),              // 5: logcache.v1.PromQL.Scalar
-	(*PromQL_Vector)(nil),              // 6: logcache.v1.PromQL.Vector
-	(*PromQL_Point)(nil),               // 7: logcache.v1.PromQL.Point
-	(*PromQL_Sample)(nil),              // 8: logcache.v1.PromQL.Sample
-	(*PromQL_Matrix)(nil),              // 9: logcache.v1.PromQL.Matrix
-	(*PromQL_Series)(nil),              // 10: logcache.v1.PromQL.Series
-	nil,                                // 11: logcache.v1.PromQL.Sample.MetricEntry
-	nil,                                // 12: logcache.v1.PromQL.Series.MetricEntry
+	(*PromQL_String)(nil),              // 6: logcache.v1.PromQL.String
+	(*PromQL_Vector)(nil),              // 7: logcache.v1.PromQL.Vector
+	(*PromQL_Point)(nil),               // 8: logcache.v1.PromQL.Point
+	(*PromQL_Sample)(nil),              // 9: logcache.v1.PromQL.Sample
+	(*PromQL_Matrix)(nil),              // 10: logcache.v1.PromQL.Matrix
+	(*PromQL_Series)(nil),              // 11: logcache.v1.PromQL.Series
+	nil,                                // 12: logcache.v1.PromQL.Sample.MetricEntry
+	nil,                                // 13: logcache.v1.PromQL.Series.MetricEntry
 }
 var file_go_log_cache_api_v1_promql_proto_depIdxs = []int32{
 	5,  // 0: logcache.v1.PromQL.InstantQueryResult.scalar:type_name -> logcache.v1.PromQL.Scalar
-	6,  // 1: logcache.v1.PromQL.InstantQueryResult.vector:type_name -> logcache.v1.PromQL.Vector
-	9,  // 2: logcache.v1.PromQL.InstantQueryResult.matrix:type_name -> logcache.v1.PromQL.Matrix
-	9,  // 3: logcache.v1.PromQL.RangeQueryResult.matrix:type_name -> logcache.v1.PromQL.Matrix
-	8,  // 4: logcache.v1.PromQL.Vector.samples:type_name -> logcache.v1.PromQL.Sample
-	11, // 5: logcache.v1.PromQL.Sample.metric:type_name -> logcache.v1.PromQL.Sample.MetricEntry
-	7,  // 6: logcache.v1.PromQL.Sample.point:type_name -> logcache.v1.PromQL.Point
-	10, // 7: logcache.v1.PromQL.Matrix.series:type_name -> logcache.v1.PromQL.Series
-	12, // 8: logcache.v1.PromQL.Series.metric:type_name -> logcache.v1.PromQL.Series.MetricEntry
-	7,  // 9: logcache.v1.PromQL.Series.points:type_name -> logcache.v1.PromQL.Point
-	1,  // 10: logcache.v1.PromQLQuerier.InstantQuery:input_type -> logcache.v1.PromQL.InstantQueryRequest
-	2,  // 11: logcache.v1.PromQLQuerier.RangeQuery:input_type -> logcache.v1.PromQL.RangeQueryRequest
-	3,  // 12: logcache.v1.PromQLQuerier.InstantQuery:output_type -> logcache.v1.PromQL.InstantQueryResult
-	4,  // 13: logcache.v1.PromQLQuerier.RangeQuery:output_type -> logcache.v1.PromQL.RangeQueryResult
-	12, // [12:14] is the sub-list for method output_type
-	10, // [10:12] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	7,  // 1: logcache.v1.PromQL.InstantQueryResult.vector:type_name -> logcache.v1.PromQL.Vector
+	10, // 2: logcache.v1.PromQL.InstantQueryResult.matrix:type_name -> logcache.v1.PromQL.Matrix
+	6,  // 3: logcache.v1.PromQL.InstantQueryResult.string_value:type_name -> logcache.v1.PromQL.String
+	10, // 4: logcache.v1.PromQL.RangeQueryResult.matrix:type_name -> logcache.v1.PromQL.Matrix
+	9,  // 5: logcache.v1.PromQL.Vector.samples:type_name -> logcache.v1.PromQL.Sample
+	12, // 6: logcache.v1.PromQL.Sample.metric:type_name -> logcache.v1.PromQL.Sample.MetricEntry
+	8,  // 7: logcache.v1.PromQL.Sample.point:type_name -> logcache.v1.PromQL.Point
+	11, // 8: logcache.v1.PromQL.Matrix.series:type_name -> logcache.v1.PromQL.Series
+	13, // 9: logcache.v1.PromQL.Series.metric:type_name -> logcache.v1.PromQL.Series.MetricEntry
+	8,  // 10: logcache.v1.PromQL.Series.points:type_name -> logcache.v1.PromQL.Point
+	1,  // 11: logcache.v1.PromQLQuerier.InstantQuery:input_type -> logcache.v1.PromQL.InstantQueryRequest
+	2,  // 12: logcache.v1.PromQLQuerier.RangeQuery:input_type -> logcache.v1.PromQL.RangeQueryRequest
+	3,  // 13: logcache.v1.PromQLQuerier.InstantQuery:output_type -> logcache.v1.PromQL.InstantQueryResult
+	4,  // 14: logcache.v1.PromQLQuerier.RangeQuery:output_type -> logcache.v1.PromQL.RangeQueryResult
+	13, // [13:15] is the sub-list for method output_type
+	11, // [11:13] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_go_log_cache_api_v1_promql_proto_init() }
@@ -886,7 +1040,7 @@ func file_go_log_cache_api_v1_promql_proto_init() {
 			}
 		}
 		file_go_log_cache_api_v1_promql_proto_msgTypes[6].Exporter = func(v any, i int) any {
-			switch v := v.(*PromQL_Vector); i {
+			switch v := v.(*PromQL_String); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -898,7 +1052,7 @@ func file_go_log_cache_api_v1_promql_proto_init() {
 			}
 		}
 		file_go_log_cache_api_v1_promql_proto_msgTypes[7].Exporter = func(v any, i int) any {
-			switch v := v.(*PromQL_Point); i {
+			switch v := v.(*PromQL_Vector); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -910,7 +1064,7 @@ func file_go_log_cache_api_v1_promql_proto_init() {
 			}
 		}
 		file_go_log_cache_api_v1_promql_proto_msgTypes[8].Exporter = func(v any, i int) any {
-			switch v := v.(*PromQL_Sample); i {
+			switch v := v.(*PromQL_Point); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -922,7 +1076,7 @@ func file_go_log_cache_api_v1_promql_proto_init() {
 			}
 		}
 		file_go_log_cache_api_v1_promql_proto_msgTypes[9].Exporter = func(v any, i int) any {
-			switch v := v.(*PromQL_Matrix); i {
+			switch v := v.(*PromQL_Sample); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -934,6 +1088,18 @@ func file_go_log_cache_api_v1_promql_proto_init() {
 			}
 		}
 		file_go_log_cache_api_v1_promql_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*PromQL_Matrix); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_go_log_cache_api_v1_promql_proto_msgTypes[11].Exporter = func(v any, i int) any {
 			switch v := v.(*PromQL_Series); i {
 			case 0:
 				return &v.state
@@ -950,6 +1116,7 @@ func file_go_log_cache_api_v1_promql_proto_init() {
 		(*PromQL_InstantQueryResult_Scalar)(nil),
 		(*PromQL_InstantQueryResult_Vector)(nil),
 		(*PromQL_InstantQueryResult_Matrix)(nil),
+		(*PromQL_InstantQueryResult_StringValue)(nil),
 	}
 	file_go_log_cache_api_v1_promql_proto_msgTypes[4].OneofWrappers = []any{
 		(*PromQL_RangeQueryResult_Matrix)(nil),
@@ -960,7 +1127,7 @@ func file_go_log_cache_api_v1_promql_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_go_log_cache_api_v1_promql_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
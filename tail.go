@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"hash/fnv"
+	"iter"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+	"google.golang.org/protobuf/proto"
+)
+
+// Tail follows the given source ID indefinitely via Read. See Tail for
+// details.
+func (c *Client) Tail(
+	ctx context.Context,
+	sourceID string,
+	opts ...TailOption,
+) iter.Seq2[*loggregator_v2.Envelope, error] {
+	return Tail(ctx, sourceID, c.Read, opts...)
+}
+
+// Tail follows the given source ID indefinitely. It yields each envelope
+// once, in the order in which it is read. Errors from the Reader are yielded
+// as well, after which Tail keeps following the source ID. Tail stops once
+// the context is done or the loop over the iterator is exited.
+//
+// Tail is built on Walk. By default it retries empty batches and errors every
+// second and only yields envelopes that are at least a second old, so that
+// envelopes from other LogCache nodes with slightly skewed clocks are not
+// skipped. Use WithTailWalkOptions to change this.
+func Tail(
+	ctx context.Context,
+	sourceID string,
+	r Reader,
+	opts ...TailOption,
+) iter.Seq2[*loggregator_v2.Envelope, error] {
+	c := tailConfig{
+		start: time.Now(),
+	}
+
+	for _, o := range opts {
+		o.configure(&c)
+	}
+
+	return func(yield func(*loggregator_v2.Envelope, error) bool) {
+		cursor := c.cursor
+		if cursor == nil {
+			cursor = NewTailCursor(c.start)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		reader := func(
+			ctx context.Context,
+			sourceID string,
+			start time.Time,
+			opts ...ReadOption,
+		) ([]*loggregator_v2.Envelope, error) {
+			es, err := r(ctx, sourceID, start, opts...)
+			if err != nil && ctx.Err() == nil && !yield(nil, err) {
+				cancel()
+			}
+
+			return es, err
+		}
+
+		visitor := func(es []*loggregator_v2.Envelope) bool {
+			for _, e := range es {
+				if !cursor.advance(e) {
+					continue
+				}
+
+				if !yield(e, nil) {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		walkOpts := []WalkOption{
+			WithWalkBackoff(NewAlwaysRetryBackoff(time.Second)),
+			WithWalkDelay(time.Second),
+		}
+		walkOpts = append(walkOpts, c.walkOpts...)
+
+		// Start at the timestamp of the cursor (and not after it) as there
+		// might be envelopes with the same timestamp that have not been
+		// yielded yet.
+		walkOpts = append(walkOpts, WithWalkStartTime(cursor.Timestamp()))
+
+		Walk(ctx, sourceID, visitor, reader, walkOpts...)
+	}
+}
+
+// TailOption configures Tail.
+type TailOption interface {
+	configure(c *tailConfig)
+}
+
+// WithTailStartTime sets the time to start following the source ID from. It
+// defaults to now. It is ignored if WithTailCursor is used.
+func WithTailStartTime(t time.Time) TailOption {
+	return tailOptionFunc(func(c *tailConfig) {
+		c.start = t
+	})
+}
+
+// WithTailCursor sets the TailCursor that records the progress of Tail. Tail
+// resumes from the cursor and keeps updating it.
+func WithTailCursor(cursor *TailCursor) TailOption {
+	return tailOptionFunc(func(c *tailConfig) {
+		c.cursor = cursor
+	})
+}
+
+// WithTailWalkOptions sets options for the underlying Walk. The start time
+// is always set by Tail.
+func WithTailWalkOptions(opts ...WalkOption) TailOption {
+	return tailOptionFunc(func(c *tailConfig) {
+		c.walkOpts = append(c.walkOpts, opts...)
+	})
+}
+
+type tailConfig struct {
+	start    time.Time
+	cursor   *TailCursor
+	walkOpts []WalkOption
+}
+
+// tailOptionFunc enables functions to implement TailOption.
+type tailOptionFunc func(c *tailConfig)
+
+// configure implements TailOption.
+func (f tailOptionFunc) configure(c *tailConfig) {
+	f(c)
+}
+
+// TailCursor records how far Tail has progressed. Passing the same
+// TailCursor to a later Tail (see WithTailCursor) resumes where the previous
+// one stopped without yielding any envelope twice.
+type TailCursor struct {
+	mu        sync.Mutex
+	timestamp int64
+
+	// seen holds the keys of the envelopes that have been yielded with the
+	// current timestamp.
+	seen map[uint64]struct{}
+}
+
+// NewTailCursor returns a TailCursor that starts at the given time.
+func NewTailCursor(start time.Time) *TailCursor {
+	return &TailCursor{
+		timestamp: start.UnixNano(),
+		seen:      make(map[uint64]struct{}),
+	}
+}
+
+// Timestamp returns the timestamp of the most recently yielded envelope, or
+// the start time if there has not been any.
+func (c *TailCursor) Timestamp() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return time.Unix(0, c.timestamp)
+}
+
+// advance records the envelope. It returns false if the envelope is older
+// than the cursor or has been recorded before.
+func (c *TailCursor) advance(e *loggregator_v2.Envelope) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ts := e.GetTimestamp()
+	switch {
+	case ts < c.timestamp:
+		return false
+	case ts > c.timestamp:
+		c.timestamp = ts
+		c.seen = make(map[uint64]struct{})
+	}
+
+	key := envelopeKey(e)
+	if _, ok := c.seen[key]; ok {
+		return false
+	}
+	c.seen[key] = struct{}{}
+
+	return true
+}
+
+// envelopeKey identifies an envelope by its content. Envelopes do not carry
+// a unique ID, so identical envelopes are considered the same.
+func envelopeKey(e *loggregator_v2.Envelope) uint64 {
+	data, _ := proto.MarshalOptions{Deterministic: true}.Marshal(e)
+
+	h := fnv.New64a()
+	h.Write(data) //nolint:errcheck
+	return h.Sum64()
+}
@@ -0,0 +1,131 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	client "code.cloudfoundry.org/go-log-cache/v3"
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+)
+
+func TestTailYieldsEnvelopesAndErrors(t *testing.T) {
+	t.Parallel()
+
+	r := newStubReader()
+	r.envelopes = [][]*loggregator_v2.Envelope{
+		{{Timestamp: 1}, {Timestamp: 2}},
+		nil,
+		{{Timestamp: 3}},
+	}
+	r.errs = []error{nil, errors.New("some-error"), nil}
+
+	var (
+		timestamps []int64
+		errs       []error
+	)
+	for e, err := range client.Tail(context.Background(), "some-id", r.read,
+		client.WithTailStartTime(time.Unix(0, 0)),
+		client.WithTailWalkOptions(client.WithWalkBackoff(client.NewAlwaysRetryBackoff(time.Millisecond))),
+	) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		timestamps = append(timestamps, e.GetTimestamp())
+		if len(timestamps) == 3 {
+			break
+		}
+	}
+
+	if len(timestamps) != 3 || timestamps[0] != 1 || timestamps[1] != 2 || timestamps[2] != 3 {
+		t.Fatalf("wrong envelopes: %v", timestamps)
+	}
+
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error: %v", errs)
+	}
+}
+
+func TestTailResumesFromCursorWithoutDuplicates(t *testing.T) {
+	t.Parallel()
+
+	cursor := client.NewTailCursor(time.Unix(0, 0))
+	walkOpt := client.WithTailWalkOptions(client.WithWalkBackoff(client.NewAlwaysRetryBackoff(time.Millisecond)))
+
+	r := newStubReader()
+	r.envelopes = [][]*loggregator_v2.Envelope{
+		{{Timestamp: 1}, {Timestamp: 2, SourceId: "a"}, {Timestamp: 2, SourceId: "b"}},
+	}
+	r.errs = []error{nil}
+
+	var count int
+	for _, err := range client.Tail(context.Background(), "some-id", r.read, client.WithTailCursor(cursor), walkOpt) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		count++
+		if count == 2 {
+			break
+		}
+	}
+
+	if cursor.Timestamp().UnixNano() != 2 {
+		t.Fatalf("expected cursor to be at 2: %d", cursor.Timestamp().UnixNano())
+	}
+
+	r = newStubReader()
+	r.envelopes = [][]*loggregator_v2.Envelope{
+		{{Timestamp: 2, SourceId: "a"}, {Timestamp: 2, SourceId: "b"}, {Timestamp: 3}},
+	}
+	r.errs = []error{nil}
+
+	var es []*loggregator_v2.Envelope
+	for e, err := range client.Tail(context.Background(), "some-id", r.read, client.WithTailCursor(cursor), walkOpt) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		es = append(es, e)
+		if len(es) == 2 {
+			break
+		}
+	}
+
+	if r.starts[0] != 2 {
+		t.Fatalf("expected to resume at the cursor: %d", r.starts[0])
+	}
+
+	if es[0].GetSourceId() != "b" || es[1].GetTimestamp() != 3 {
+		t.Fatalf("wrong envelopes: %v", es)
+	}
+
+	if cursor.Timestamp().UnixNano() != 3 {
+		t.Fatalf("expected cursor to be at 3: %d", cursor.Timestamp().UnixNano())
+	}
+}
+
+func TestTailStopsWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	r := newStubReader()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range client.Tail(ctx, "some-id", r.read, client.WithTailStartTime(time.Unix(0, 0))) {
+			t.Error("expected no envelopes")
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Tail to stop")
+	}
+}
@@ -24,7 +24,10 @@ type Reader func(
 // batch of envelopes.
 type Visitor func([]*loggregator_v2.Envelope) bool
 
-// Walk reads from the LogCache until the Visitor returns false.
+// Walk reads from the LogCache until the Visitor returns false. If a batch
+// might end in the middle of a group of envelopes with the same timestamp,
+// Walk re-reads that timestamp and only visits the envelopes it has not
+// visited yet.
 func Walk(ctx context.Context, sourceID string, v Visitor, r Reader, opts ...WalkOption) {
 	c := &WalkConfig{
 		Log:     log.New(io.Discard, "", 0),
@@ -37,58 +40,61 @@ func Walk(ctx context.Context, sourceID string, v Visitor, r Reader, opts ...Wal
 		o(c)
 	}
 
-	var readOpts []ReadOption
-	if !c.End.IsZero() {
-		readOpts = append(readOpts, WithEndTime(c.End))
-	}
-
-	if c.Limit != nil {
-		readOpts = append(readOpts, WithLimit(*c.Limit))
+	if !c.loadCheckpoint(ctx, sourceID) {
+		return
 	}
 
-	if c.EnvelopeTypes != nil {
-		readOpts = append(readOpts, WithEnvelopeTypes(c.EnvelopeTypes...))
-	}
-
-	if c.NameFilter != "" {
-		readOpts = append(readOpts, WithNameFilter(c.NameFilter))
+	readOpts := c.filterOptions()
+	if !c.End.IsZero() {
+		readOpts = append(readOpts, WithEndTime(c.End))
 	}
 
-	var receivedEmpty bool
+	var (
+		receivedEmpty bool
+		b             = newBoundary(c.Limit)
+		capped        = newEnvelopeCap(c.MaxEnvelopes)
+	)
 
 	for {
-		es, err := r(ctx, sourceID, time.Unix(0, c.Start), readOpts...)
-		if err != nil && ctx.Err() != nil {
-			// Context cancelled
-			return
-		}
-
+		raw, err := r(ctx, sourceID, time.Unix(0, c.Start), b.withLimit(readOpts)...)
 		if err != nil {
-			c.Log.Print(err)
-			if !c.Backoff.OnErr(err) {
+			if !retryRead(ctx, c, err) {
 				return
 			}
 			continue
 		}
 
-		if c.End.IsZero() || !receivedEmpty {
-			// Prune envelopes for any that are too new or from the future.
-			es = c.DelayFunc(es)
-		}
+		full := b.full(len(raw))
 
-		if !c.End.IsZero() {
-			for i := len(es) - 1; i >= 0; i-- {
-				if es[i].GetTimestamp() < c.End.UnixNano() {
-					break
+		// Prune envelopes for any that are too new or from the future.
+		es := c.trim(raw, c.End.IsZero() || !receivedEmpty)
+		truncated := truncated(raw, es, full)
+		untrimmed := len(es) == len(raw)
+		es = b.prune(es)
+
+		if len(es) == 0 {
+			if b.rereading() && full && untrimmed {
+				// The whole batch has been visited already, so there are
+				// more envelopes with the timestamp c.Start than fit into a
+				// batch.
+				if !b.boost(c.Log) {
+					c.Start++
 				}
+				continue
+			}
 
-				es = es[:i]
+			if b.rereading() {
+				// All the envelopes with the timestamp c.Start have been
+				// visited, so it is not read again.
+				b.reset()
+				c.Start++
+				if !c.End.IsZero() && c.Start >= c.End.UnixNano() {
+					return
+				}
 			}
-		}
 
-		if len(es) == 0 {
 			receivedEmpty = true
-			if !c.Backoff.OnEmpty() {
+			if !backoffOnEmpty(ctx, c.Backoff) {
 				return
 			}
 			continue
@@ -97,14 +103,252 @@ func Walk(ctx context.Context, sourceID string, v Visitor, r Reader, opts ...Wal
 		c.Backoff.Reset()
 		receivedEmpty = false
 
-		// If visitor is done or the next timestamp would be outside of our
-		// window (only when End is set), then be done.
-		if !v(es) || (!c.End.IsZero() && es[len(es)-1].Timestamp+1 >= c.End.UnixNano()) {
+		es, done := capped.take(es)
+		last := es[len(es)-1].Timestamp
+
+		if !v(es) {
+			return
+		}
+
+		c.saveCheckpoint(ctx, sourceID, last)
+
+		if done {
+			return
+		}
+
+		if truncated {
+			// Re-read the last timestamp to not skip any of its envelopes.
+			c.Start = last
+			b.reread(es, last)
+			continue
+		}
+
+		// If the next timestamp would be outside of our window (only when End
+		// is set), then be done.
+		if !c.End.IsZero() && last+1 >= c.End.UnixNano() {
 			return
 		}
 
-		c.Start = es[len(es)-1].Timestamp + 1
+		c.Start = last + 1
+		b.reset()
+	}
+}
+
+// filterOptions returns the ReadOptions for the envelope types and name
+// filter of the walk.
+func (c *WalkConfig) filterOptions() []ReadOption {
+	var opts []ReadOption
+	if c.EnvelopeTypes != nil {
+		opts = append(opts, WithEnvelopeTypes(c.EnvelopeTypes...))
+	}
+
+	if c.NameFilter != "" {
+		opts = append(opts, WithNameFilter(c.NameFilter))
+	}
+
+	return opts
+}
+
+// trim removes the envelopes that are too new, if delay is set, and the
+// envelopes that are not before the end time.
+func (c *WalkConfig) trim(es []*loggregator_v2.Envelope, delay bool) []*loggregator_v2.Envelope {
+	if delay {
+		es = c.DelayFunc(es)
+	}
+
+	if c.End.IsZero() {
+		return es
+	}
+
+	for i := len(es) - 1; i >= 0; i-- {
+		if es[i].GetTimestamp() < c.End.UnixNano() {
+			break
+		}
+
+		es = es[:i]
+	}
+
+	return es
+}
+
+// retryRead logs the error of a read and reports whether to retry it.
+func retryRead(ctx context.Context, c *WalkConfig, err error) bool {
+	if ctx.Err() != nil {
+		// Context cancelled
+		return false
+	}
+
+	c.Log.Print(err)
+	return backoffOnErr(ctx, c.Backoff, err)
+}
+
+// truncated reports whether the last timestamp of es, a prefix of the batch
+// raw, might have more envelopes than es holds, either because the batch is
+// full or because pruning cut into them.
+func truncated(raw, es []*loggregator_v2.Envelope, full bool) bool {
+	if len(es) == 0 {
+		return false
+	}
+
+	if len(es) == len(raw) {
+		return full
+	}
+
+	return raw[len(es)].GetTimestamp() == es[len(es)-1].GetTimestamp()
+}
+
+const (
+	// defaultReadLimit is the number of envelopes LogCache returns if no
+	// limit is given.
+	defaultReadLimit = 100
+
+	// maxReadLimit is the maximum number of envelopes LogCache returns.
+	maxReadLimit = 1000
+)
+
+// envelopeCap limits the number of envelopes a walk visits (see
+// WithWalkMaxEnvelopes).
+type envelopeCap struct {
+	// remaining is the number of envelopes that may still be visited. It is
+	// negative if there is no maximum.
+	remaining int
+}
+
+func newEnvelopeCap(maxEnvelopes int) *envelopeCap {
+	if maxEnvelopes <= 0 {
+		return &envelopeCap{remaining: -1}
+	}
+
+	return &envelopeCap{remaining: maxEnvelopes}
+}
+
+// take returns the envelopes of the batch that may still be visited and
+// whether the maximum is reached with them.
+func (c *envelopeCap) take(es []*loggregator_v2.Envelope) ([]*loggregator_v2.Envelope, bool) {
+	if c.remaining < 0 {
+		return es, false
+	}
+
+	es = es[:min(len(es), c.remaining)]
+	c.remaining -= len(es)
+	return es, c.remaining == 0
+}
+
+// boundary tracks the timestamp at which a batch ended, so that it can be
+// read again without skipping or revisiting envelopes that share it.
+type boundary struct {
+	// limit is the limit of each read, unless it is boosted. readLimit is
+	// the number of envelopes LogCache returns for it.
+	limit     *int
+	readLimit int
+
+	// boostedLimit is the limit used to read past a group of envelopes
+	// with the same timestamp that does not fit into a single batch.
+	boostedLimit int
+
+	// seen holds the keys of the envelopes that have been visited with the
+	// timestamp ts. It is only set while the timestamp is re-read.
+	ts   int64
+	seen map[uint64]struct{}
+}
+
+func newBoundary(limit *int) *boundary {
+	b := &boundary{
+		limit:     limit,
+		readLimit: defaultReadLimit,
+	}
+
+	if limit != nil && *limit > 0 {
+		b.readLimit = min(*limit, maxReadLimit)
+	}
+
+	return b
+}
+
+// withLimit appends the limit of the next read to the options.
+func (b *boundary) withLimit(opts []ReadOption) []ReadOption {
+	switch {
+	case b.boostedLimit > 0:
+		return append(opts[:len(opts):len(opts)], WithLimit(b.boostedLimit))
+	case b.limit != nil:
+		return append(opts[:len(opts):len(opts)], WithLimit(*b.limit))
+	default:
+		return opts
+	}
+}
+
+// full reports whether a batch of n envelopes is as large as the read
+// allows.
+func (b *boundary) full(n int) bool {
+	return n >= max(b.readLimit, b.boostedLimit)
+}
+
+// rereading reports whether the timestamp of the boundary is being read
+// again.
+func (b *boundary) rereading() bool {
+	return b.seen != nil
+}
+
+// prune removes the envelopes that have been visited before.
+func (b *boundary) prune(es []*loggregator_v2.Envelope) []*loggregator_v2.Envelope {
+	return pruneSeen(es, b.ts, b.seen)
+}
+
+// reread records the envelopes of the batch with the given timestamp as
+// visited before the timestamp is read again.
+func (b *boundary) reread(es []*loggregator_v2.Envelope, ts int64) {
+	if b.seen == nil || b.ts != ts {
+		b.reset()
+		b.ts = ts
+		b.seen = make(map[uint64]struct{})
+	}
+
+	for _, e := range es {
+		if e.GetTimestamp() == ts {
+			b.seen[envelopeKey(e)] = struct{}{}
+		}
+	}
+}
+
+// boost raises the limit after a full batch of envelopes that have all been
+// visited before. If the limit can't be raised any further, it gives up on
+// the timestamp and returns false.
+func (b *boundary) boost(l *log.Logger) bool {
+	if b.boostedLimit < maxReadLimit {
+		b.boostedLimit = min(max(b.readLimit, b.boostedLimit)*2, maxReadLimit)
+		return true
+	}
+
+	l.Printf("skipping envelopes with timestamp %d: more than %d envelopes share it", b.ts, maxReadLimit)
+	b.reset()
+	return false
+}
+
+// reset stops reading the timestamp again.
+func (b *boundary) reset() {
+	b.seen = nil
+	b.boostedLimit = 0
+}
+
+// pruneSeen removes the envelopes with the given timestamp that have been
+// seen before.
+func pruneSeen(es []*loggregator_v2.Envelope, ts int64, seen map[uint64]struct{}) []*loggregator_v2.Envelope {
+	if len(seen) == 0 {
+		return es
+	}
+
+	pruned := make([]*loggregator_v2.Envelope, 0, len(es))
+	for _, e := range es {
+		if e.GetTimestamp() == ts {
+			if _, ok := seen[envelopeKey(e)]; ok {
+				continue
+			}
+		}
+
+		pruned = append(pruned, e)
 	}
+
+	return pruned
 }
 
 // WalkOption overrides defaults for Walk.
@@ -153,6 +397,14 @@ func WithWalkNameFilter(nameFilter string) WalkOption {
 	}
 }
 
+// WithWalkMaxEnvelopes sets the maximum number of envelopes to visit. Once
+// reached, Walk will exit. It defaults to no maximum.
+func WithWalkMaxEnvelopes(n int) WalkOption {
+	return func(c *WalkConfig) {
+		c.MaxEnvelopes = n
+	}
+}
+
 // WithWalkBackoff sets the Backoff strategy for an empty batch or error. It
 // defaults to stopping on an error or empty batch via AlwaysDoneBackoff.
 func WithWalkBackoff(b Backoff) WalkOption {
@@ -204,6 +456,35 @@ type Backoff interface {
 	Reset()
 }
 
+// ContextBackoff is a Backoff that stops waiting once the given context is
+// done. Walk uses it instead of the methods of Backoff so that it exits
+// promptly when its context is cancelled.
+type ContextBackoff interface {
+	Backoff
+	OnErrContext(ctx context.Context, err error) bool
+	OnEmptyContext(ctx context.Context) bool
+}
+
+// backoffOnErr invokes the Backoff with the context if it is supported. It
+// returns false if the context is done.
+func backoffOnErr(ctx context.Context, b Backoff, err error) bool {
+	if cb, ok := b.(ContextBackoff); ok {
+		return cb.OnErrContext(ctx, err) && ctx.Err() == nil
+	}
+
+	return b.OnErr(err) && ctx.Err() == nil
+}
+
+// backoffOnEmpty invokes the Backoff with the context if it is supported. It
+// returns false if the context is done.
+func backoffOnEmpty(ctx context.Context, b Backoff) bool {
+	if cb, ok := b.(ContextBackoff); ok {
+		return cb.OnEmptyContext(ctx) && ctx.Err() == nil
+	}
+
+	return b.OnEmpty() && ctx.Err() == nil
+}
+
 // AlwaysDoneBackoff returns false for both OnErr and OnEmpty.
 type AlwaysDoneBackoff struct{}
 
@@ -234,15 +515,23 @@ func NewAlwaysRetryBackoff(interval time.Duration) AlwaysRetryBackoff {
 }
 
 // OnErr implements Backoff.
-func (b AlwaysRetryBackoff) OnErr(error) bool {
-	time.Sleep(b.interval)
-	return true
+func (b AlwaysRetryBackoff) OnErr(err error) bool {
+	return b.OnErrContext(context.Background(), err)
 }
 
 // OnEmpty implements Backoff.
 func (b AlwaysRetryBackoff) OnEmpty() bool {
-	time.Sleep(b.interval)
-	return true
+	return b.OnEmptyContext(context.Background())
+}
+
+// OnErrContext implements ContextBackoff.
+func (b AlwaysRetryBackoff) OnErrContext(ctx context.Context, _ error) bool {
+	return sleepContext(ctx, b.interval)
+}
+
+// OnEmptyContext implements ContextBackoff.
+func (b AlwaysRetryBackoff) OnEmptyContext(ctx context.Context) bool {
+	return sleepContext(ctx, b.interval)
 }
 
 // Reset implements Backoff.
@@ -276,18 +565,27 @@ func NewRetryBackoffOnErr(interval time.Duration, maxCount int) *RetryBackoff {
 }
 
 // OnErr implements Backoff.
-func (b *RetryBackoff) OnErr(error) bool {
+func (b *RetryBackoff) OnErr(err error) bool {
+	return b.OnErrContext(context.Background(), err)
+}
+
+// OnEmpty implements Backoff.
+func (b *RetryBackoff) OnEmpty() bool {
+	return b.OnEmptyContext(context.Background())
+}
+
+// OnErrContext implements ContextBackoff.
+func (b *RetryBackoff) OnErrContext(ctx context.Context, _ error) bool {
 	b.count++
 	if b.count >= b.maxCount {
 		return false
 	}
 
-	time.Sleep(b.interval)
-	return true
+	return sleepContext(ctx, b.interval)
 }
 
-// OnEmpty implements Backoff.
-func (b *RetryBackoff) OnEmpty() bool {
+// OnEmptyContext implements ContextBackoff.
+func (b *RetryBackoff) OnEmptyContext(ctx context.Context) bool {
 	if b.onlyErr {
 		return false
 	}
@@ -297,8 +595,7 @@ func (b *RetryBackoff) OnEmpty() bool {
 		return false
 	}
 
-	time.Sleep(b.interval)
-	return true
+	return sleepContext(ctx, b.interval)
 }
 
 // Reset implements Backoff.
@@ -306,6 +603,88 @@ func (b *RetryBackoff) Reset() {
 	b.count = 0
 }
 
+// ExponentialBackoffPolicy configures how long ExponentialBackoff waits. The
+// n-th consecutive retry waits Base*Multiplier^(n-1), capped at Max and
+// randomized by Jitter.
+type ExponentialBackoffPolicy struct {
+	// Base is the delay before the first retry. A zero Base never retries.
+	Base time.Duration
+
+	// Multiplier defaults to 2.
+	Multiplier float64
+
+	// Max caps the delay. A zero Max does not cap it.
+	Max time.Duration
+
+	// Jitter randomizes each delay by up to the given fraction, e.g. 0.2
+	// results in delays between 80% and 120% of the computed delay.
+	Jitter float64
+
+	// MaxRetries is the number of consecutive retries before giving up. A
+	// zero MaxRetries retries forever.
+	MaxRetries int
+}
+
+func (p ExponentialBackoffPolicy) wait(ctx context.Context, count int) bool {
+	if p.Base <= 0 || (p.MaxRetries > 0 && count > p.MaxRetries) {
+		return false
+	}
+
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	return sleepContext(ctx, exponentialDelay(count-1, p.Base, multiplier, p.Max, p.Jitter))
+}
+
+// ExponentialBackoff waits exponentially longer for each consecutive error or
+// empty batch. Errors and empty batches are counted and configured
+// separately.
+type ExponentialBackoff struct {
+	onErr   ExponentialBackoffPolicy
+	onEmpty ExponentialBackoffPolicy
+
+	errCount   int
+	emptyCount int
+}
+
+// NewExponentialBackoff returns a new ExponentialBackoff.
+func NewExponentialBackoff(onErr, onEmpty ExponentialBackoffPolicy) *ExponentialBackoff {
+	return &ExponentialBackoff{
+		onErr:   onErr,
+		onEmpty: onEmpty,
+	}
+}
+
+// OnErr implements Backoff.
+func (b *ExponentialBackoff) OnErr(err error) bool {
+	return b.OnErrContext(context.Background(), err)
+}
+
+// OnEmpty implements Backoff.
+func (b *ExponentialBackoff) OnEmpty() bool {
+	return b.OnEmptyContext(context.Background())
+}
+
+// OnErrContext implements ContextBackoff.
+func (b *ExponentialBackoff) OnErrContext(ctx context.Context, _ error) bool {
+	b.errCount++
+	return b.onErr.wait(ctx, b.errCount)
+}
+
+// OnEmptyContext implements ContextBackoff.
+func (b *ExponentialBackoff) OnEmptyContext(ctx context.Context) bool {
+	b.emptyCount++
+	return b.onEmpty.wait(ctx, b.emptyCount)
+}
+
+// Reset implements Backoff.
+func (b *ExponentialBackoff) Reset() {
+	b.errCount = 0
+	b.emptyCount = 0
+}
+
 type WalkConfig struct {
 	Log           *log.Logger
 	Backoff       Backoff
@@ -315,4 +694,6 @@ type WalkConfig struct {
 	EnvelopeTypes []logcache_v1.EnvelopeType
 	DelayFunc     func([]*loggregator_v2.Envelope) []*loggregator_v2.Envelope
 	NameFilter    string
+	Checkpointer  Checkpointer
+	MaxEnvelopes  int
 }
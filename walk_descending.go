@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+)
+
+// WalkDescending reads from the LogCache backwards, starting with the
+// newest envelopes before the end time (which defaults to now). Each batch
+// is passed to the Visitor in descending order. WalkDescending exits once
+// the Visitor returns false, the start time is reached or the number of
+// envelopes set via WithWalkMaxEnvelopes has been visited.
+//
+// Like Walk, WalkDescending does not skip envelopes that share a timestamp
+// across batches. The Backoff is only consulted for errors as an empty
+// batch means the start time is reached. WithWalkDelay, WithWalkDelayFunc
+// and WithWalkCheckpointer do not apply.
+func WalkDescending(ctx context.Context, sourceID string, v Visitor, r Reader, opts ...WalkOption) {
+	c := &WalkConfig{
+		Log:     log.New(io.Discard, "", 0),
+		Backoff: AlwaysDoneBackoff{},
+	}
+
+	for _, o := range opts {
+		o(c)
+	}
+
+	readOpts := append(c.filterOptions(), WithDescending())
+
+	end := c.End.UnixNano()
+	if c.End.IsZero() {
+		end = time.Now().UnixNano()
+	}
+
+	var (
+		b      = newBoundary(c.Limit)
+		capped = newEnvelopeCap(c.MaxEnvelopes)
+	)
+
+	for end > c.Start {
+		opts := append(readOpts[:len(readOpts):len(readOpts)], WithEndTime(time.Unix(0, end)))
+		es, err := r(ctx, sourceID, time.Unix(0, c.Start), b.withLimit(opts)...)
+		if err != nil {
+			if !retryRead(ctx, c, err) {
+				return
+			}
+			continue
+		}
+
+		full := b.full(len(es))
+		es = b.prune(es)
+
+		if len(es) == 0 {
+			if !full || !b.rereading() {
+				// Everything has been read.
+				return
+			}
+
+			// The whole batch has been visited already, so there are more
+			// envelopes with the timestamp end-1 than fit into a batch.
+			if !b.boost(c.Log) {
+				end--
+			}
+			continue
+		}
+
+		c.Backoff.Reset()
+
+		es, done := capped.take(es)
+		if !v(es) || !full || done {
+			return
+		}
+
+		// Re-read the oldest timestamp to not skip any of its envelopes.
+		oldest := es[len(es)-1].GetTimestamp()
+		end = oldest + 1
+		b.reread(es, oldest)
+	}
+}
+
+// ReverseWalker walks a reader backwards. It returns up to the given number
+// of envelopes before the end time in descending order.
+type ReverseWalker func(
+	ctx context.Context,
+	end time.Time,
+	n int,
+) []*loggregator_v2.Envelope
+
+// BuildReverseWalker captures the sourceID, reader and options to be used
+// with a ReverseWalker.
+func BuildReverseWalker(sourceID string, r Reader, opts ...WalkOption) ReverseWalker {
+	return func(ctx context.Context, end time.Time, n int) []*loggregator_v2.Envelope {
+		var results []*loggregator_v2.Envelope
+		opts := append(opts[:len(opts):len(opts)],
+			WithWalkEndTime(end),
+			WithWalkMaxEnvelopes(n),
+		)
+
+		WalkDescending(ctx, sourceID, func(e []*loggregator_v2.Envelope) bool {
+			results = append(results, e...)
+			return true
+		}, r, opts...)
+
+		return results
+	}
+}
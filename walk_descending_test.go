@@ -0,0 +1,159 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	client "code.cloudfoundry.org/go-log-cache/v3"
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+)
+
+func TestWalkDescending(t *testing.T) {
+	t.Parallel()
+
+	var store []*loggregator_v2.Envelope
+	for i := 1; i <= 50; i++ {
+		store = append(store, &loggregator_v2.Envelope{Timestamp: int64(i)})
+	}
+
+	r := newLimitReader(store)
+	var es []*loggregator_v2.Envelope
+	client.WalkDescending(
+		context.Background(),
+		"some-id",
+		func(b []*loggregator_v2.Envelope) bool {
+			es = append(es, b...)
+			return true
+		},
+		r.read,
+		client.WithWalkStartTime(time.Unix(0, 10)),
+		client.WithWalkEndTime(time.Unix(0, 41)),
+		client.WithWalkLimit(7),
+	)
+
+	if len(es) != 31 {
+		t.Fatalf("expected 31 envelopes: %d", len(es))
+	}
+
+	for i, e := range es {
+		if e.Timestamp != int64(40-i) {
+			t.Fatalf("expected envelopes in descending order: %d at %d", e.Timestamp, i)
+		}
+	}
+}
+
+func TestWalkDescendingStopsAtMaxEnvelopes(t *testing.T) {
+	t.Parallel()
+
+	var store []*loggregator_v2.Envelope
+	for i := 1; i <= 50; i++ {
+		store = append(store, &loggregator_v2.Envelope{Timestamp: int64(i)})
+	}
+
+	r := newLimitReader(store)
+	var es []*loggregator_v2.Envelope
+	client.WalkDescending(
+		context.Background(),
+		"some-id",
+		func(b []*loggregator_v2.Envelope) bool {
+			es = append(es, b...)
+			return true
+		},
+		r.read,
+		client.WithWalkLimit(10),
+		client.WithWalkMaxEnvelopes(25),
+	)
+
+	if len(es) != 25 {
+		t.Fatalf("expected 25 envelopes: %d", len(es))
+	}
+
+	if es[0].Timestamp != 50 || es[24].Timestamp != 26 {
+		t.Fatalf("expected the newest envelopes: %d-%d", es[0].Timestamp, es[24].Timestamp)
+	}
+}
+
+func TestWalkDescendingDoesNotSkipEnvelopesWithSameTimestamp(t *testing.T) {
+	t.Parallel()
+
+	var store []*loggregator_v2.Envelope
+	for i := 0; i < 10; i++ {
+		store = append(store, &loggregator_v2.Envelope{Timestamp: 1, InstanceId: strconv.Itoa(i)})
+	}
+	for i := 0; i < 250; i++ {
+		store = append(store, &loggregator_v2.Envelope{Timestamp: 2, InstanceId: strconv.Itoa(i)})
+	}
+
+	r := newLimitReader(store)
+	seen := make(map[string]int)
+	client.WalkDescending(
+		context.Background(),
+		"some-id",
+		func(es []*loggregator_v2.Envelope) bool {
+			for _, e := range es {
+				seen[fmt.Sprintf("%d/%s", e.Timestamp, e.InstanceId)]++
+			}
+			return true
+		},
+		r.read,
+		client.WithWalkLimit(100),
+	)
+
+	if len(seen) != len(store) {
+		t.Fatalf("expected %d envelopes: %d", len(store), len(seen))
+	}
+
+	for k, n := range seen {
+		if n != 1 {
+			t.Fatalf("expected %s to be visited once: %d", k, n)
+		}
+	}
+}
+
+func TestWalkDescendingRetriesOnError(t *testing.T) {
+	t.Parallel()
+
+	r := &stubReader{
+		envelopes: [][]*loggregator_v2.Envelope{
+			nil,
+			{{Timestamp: 2}, {Timestamp: 1}},
+		},
+		errs: []error{errors.New("some-error"), nil},
+	}
+
+	var es []*loggregator_v2.Envelope
+	client.WalkDescending(
+		context.Background(),
+		"some-id",
+		func(b []*loggregator_v2.Envelope) bool {
+			es = append(es, b...)
+			return true
+		},
+		r.read,
+		client.WithWalkBackoff(client.NewRetryBackoff(time.Millisecond, 2)),
+	)
+
+	if len(es) != 2 {
+		t.Fatalf("expected 2 envelopes: %d", len(es))
+	}
+}
+
+func TestBuildReverseWalker(t *testing.T) {
+	t.Parallel()
+
+	var store []*loggregator_v2.Envelope
+	for i := 1; i <= 50; i++ {
+		store = append(store, &loggregator_v2.Envelope{Timestamp: int64(i)})
+	}
+
+	r := newLimitReader(store)
+	es := client.BuildReverseWalker("some-id", r.read)(context.Background(), time.Unix(0, 21), 5)
+
+	if len(es) != 5 || es[0].Timestamp != 20 || es[4].Timestamp != 16 {
+		t.Fatalf("wrong envelopes: %v", es)
+	}
+}
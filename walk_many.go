@@ -0,0 +1,344 @@
+package client
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+)
+
+// WalkMany walks the given source IDs concurrently and invokes the Visitor
+// with their envelopes merged in timestamp order. Each source ID is walked
+// via Walk with the options given by WithWalkManyWalkOptions, so it stops
+// according to its Backoff or end time. WalkMany returns once every source
+// ID is done, the Visitor returns false or the context is done.
+//
+// Envelopes are only visited once every source ID that is not caught up has
+// been read past their timestamp. A source ID is considered caught up while
+// its last read returned no envelopes or failed. Envelopes that arrive in
+// LogCache late, or are read after an error, might therefore be visited out
+// of order.
+func WalkMany(ctx context.Context, sourceIDs []string, v Visitor, r Reader, opts ...WalkManyOption) {
+	c := &WalkManyConfig{
+		Concurrency: 10,
+		OnError:     func(string, error) {},
+	}
+
+	for _, o := range opts {
+		o(c)
+	}
+
+	if len(sourceIDs) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	m := &walkManyMerger{
+		sources: make([]*walkManySource, len(sourceIDs)),
+		events:  make(chan walkManyEvent),
+	}
+
+	// sem bounds the number of concurrent reads.
+	sem := make(chan struct{}, max(c.Concurrency, 1))
+
+	var wg sync.WaitGroup
+	for i, sourceID := range sourceIDs {
+		m.sources[i] = &walkManySource{}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			walkOne(ctx, i, sourceID, r, sem, m.events, c)
+		}()
+	}
+
+	m.run(ctx, v)
+
+	cancel()
+	wg.Wait()
+}
+
+// walkOne walks a single source ID and reports its progress to the merger.
+func walkOne(
+	ctx context.Context,
+	i int,
+	sourceID string,
+	r Reader,
+	sem chan struct{},
+	events chan<- walkManyEvent,
+	c *WalkManyConfig,
+) {
+	send := func(ev walkManyEvent) bool {
+		ev.source = i
+		select {
+		case events <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	reader := func(
+		ctx context.Context,
+		sourceID string,
+		start time.Time,
+		opts ...ReadOption,
+	) ([]*loggregator_v2.Envelope, error) {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		es, err := r(ctx, sourceID, start, opts...)
+		<-sem
+
+		if err != nil && ctx.Err() == nil {
+			c.OnError(sourceID, err)
+		}
+
+		send(walkManyEvent{
+			kind:     walkManyRead,
+			start:    start.UnixNano(),
+			caughtUp: err != nil || len(es) == 0,
+		})
+
+		return es, err
+	}
+
+	visitor := func(es []*loggregator_v2.Envelope) bool {
+		ack := make(chan bool, 1)
+		if !send(walkManyEvent{kind: walkManyBatch, envelopes: es, ack: ack}) {
+			return false
+		}
+
+		select {
+		case ok := <-ack:
+			return ok
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	walkOpts := c.WalkOptions
+	if c.NewBackoff != nil {
+		walkOpts = append(walkOpts[:len(walkOpts):len(walkOpts)], WithWalkBackoff(c.NewBackoff()))
+	}
+
+	Walk(ctx, sourceID, visitor, reader, walkOpts...)
+	send(walkManyEvent{kind: walkManyDone})
+}
+
+// BuildMultiWalker captures the source IDs and reader to be used with a
+// Walker. The Walker merges the envelopes of all source IDs via WalkMany.
+func BuildMultiWalker(sourceIDs []string, r Reader, opts ...WalkManyOption) Walker {
+	return func(ctx context.Context, start, end time.Time) []*loggregator_v2.Envelope {
+		var results []*loggregator_v2.Envelope
+		opts := append(opts[:len(opts):len(opts)], WithWalkManyWalkOptions(
+			WithWalkStartTime(start),
+			WithWalkEndTime(end),
+		))
+
+		WalkMany(ctx, sourceIDs, func(e []*loggregator_v2.Envelope) bool {
+			results = append(results, e...)
+			return true
+		}, r, opts...)
+
+		return results
+	}
+}
+
+// WalkManyOption overrides defaults for WalkMany.
+type WalkManyOption func(config *WalkManyConfig)
+
+// WithWalkManyConcurrency sets the maximum number of concurrent reads. It
+// defaults to 10.
+func WithWalkManyConcurrency(n int) WalkManyOption {
+	return func(c *WalkManyConfig) {
+		c.Concurrency = n
+	}
+}
+
+// WithWalkManyWalkOptions sets the options for the Walk of each source ID.
+// As they are shared by all source IDs, use WithWalkManyBackoff to set a
+// Backoff that keeps state, e.g. RetryBackoff.
+func WithWalkManyWalkOptions(opts ...WalkOption) WalkManyOption {
+	return func(c *WalkManyConfig) {
+		c.WalkOptions = append(c.WalkOptions, opts...)
+	}
+}
+
+// WithWalkManyBackoff sets a function that creates the Backoff for the Walk
+// of each source ID.
+func WithWalkManyBackoff(newBackoff func() Backoff) WalkManyOption {
+	return func(c *WalkManyConfig) {
+		c.NewBackoff = newBackoff
+	}
+}
+
+// WithWalkManyErrorHandler sets the function that is invoked with every
+// error a source ID is read with. An error does not stop the other source
+// IDs. It defaults to ignoring errors.
+func WithWalkManyErrorHandler(f func(sourceID string, err error)) WalkManyOption {
+	return func(c *WalkManyConfig) {
+		c.OnError = f
+	}
+}
+
+type WalkManyConfig struct {
+	Concurrency int
+	WalkOptions []WalkOption
+	NewBackoff  func() Backoff
+	OnError     func(sourceID string, err error)
+}
+
+type walkManyEventKind int
+
+const (
+	walkManyRead walkManyEventKind = iota
+	walkManyBatch
+	walkManyDone
+)
+
+type walkManyEvent struct {
+	source int
+	kind   walkManyEventKind
+
+	// start and caughtUp are set for walkManyRead.
+	start    int64
+	caughtUp bool
+
+	// envelopes and ack are set for walkManyBatch.
+	envelopes []*loggregator_v2.Envelope
+	ack       chan<- bool
+}
+
+type walkManySource struct {
+	// low is the lowest timestamp the next envelope of the source can have.
+	low      int64
+	read     bool
+	caughtUp bool
+	done     bool
+
+	// pending is the number of envelopes of the source that have not been
+	// visited yet. The Walk of the source waits for ack until all of them
+	// have been visited.
+	pending int
+	ack     chan<- bool
+}
+
+type walkManyMerger struct {
+	sources []*walkManySource
+	events  chan walkManyEvent
+	heap    envelopeHeap
+	seq     int
+}
+
+func (m *walkManyMerger) run(ctx context.Context, v Visitor) {
+	remaining := len(m.sources)
+	for remaining > 0 {
+		var ev walkManyEvent
+		select {
+		case ev = <-m.events:
+		case <-ctx.Done():
+			return
+		}
+
+		s := m.sources[ev.source]
+		switch ev.kind {
+		case walkManyRead:
+			s.read = true
+			s.low = max(s.low, ev.start)
+			s.caughtUp = ev.caughtUp
+		case walkManyBatch:
+			for _, e := range ev.envelopes {
+				heap.Push(&m.heap, envelopeHeapItem{envelope: e, source: ev.source, seq: m.seq})
+				m.seq++
+			}
+			s.pending += len(ev.envelopes)
+			s.low = max(s.low, ev.envelopes[len(ev.envelopes)-1].GetTimestamp())
+			s.ack = ev.ack
+		case walkManyDone:
+			s.done = true
+			remaining--
+		}
+
+		if !m.visit(v) {
+			return
+		}
+	}
+}
+
+// visit invokes the Visitor with all envelopes up to the watermark. It
+// returns false if the Visitor does.
+func (m *walkManyMerger) visit(v Visitor) bool {
+	watermark := int64(math.MaxInt64)
+	for _, s := range m.sources {
+		if s.done || (s.read && s.caughtUp) {
+			continue
+		}
+
+		if !s.read {
+			// Nothing is known about the source yet.
+			return true
+		}
+
+		watermark = min(watermark, s.low)
+	}
+
+	var es []*loggregator_v2.Envelope
+	for m.heap.Len() > 0 && m.heap[0].envelope.GetTimestamp() <= watermark {
+		item := heap.Pop(&m.heap).(envelopeHeapItem)
+		es = append(es, item.envelope)
+		m.sources[item.source].pending--
+	}
+
+	if len(es) > 0 && !v(es) {
+		return false
+	}
+
+	for _, s := range m.sources {
+		if s.ack != nil && s.pending == 0 {
+			s.ack <- true
+			s.ack = nil
+		}
+	}
+
+	return true
+}
+
+type envelopeHeapItem struct {
+	envelope *loggregator_v2.Envelope
+	source   int
+	seq      int
+}
+
+// envelopeHeap orders envelopes by timestamp and then by the order they were
+// pushed in.
+type envelopeHeap []envelopeHeapItem
+
+func (h envelopeHeap) Len() int { return len(h) }
+
+func (h envelopeHeap) Less(i, j int) bool {
+	ti, tj := h[i].envelope.GetTimestamp(), h[j].envelope.GetTimestamp()
+	if ti != tj {
+		return ti < tj
+	}
+
+	return h[i].seq < h[j].seq
+}
+
+func (h envelopeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *envelopeHeap) Push(x any) { *h = append(*h, x.(envelopeHeapItem)) }
+
+func (h *envelopeHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
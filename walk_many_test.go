@@ -0,0 +1,257 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	client "code.cloudfoundry.org/go-log-cache/v3"
+	"code.cloudfoundry.org/go-loggregator/v10/rpc/loggregator_v2"
+)
+
+func TestWalkManyMergesInTimestampOrder(t *testing.T) {
+	t.Parallel()
+
+	r := newMultiReader(map[string][]int64{
+		"a": {1, 4, 7, 10},
+		"b": {2, 5, 8},
+		"c": {3, 6, 9},
+	})
+
+	var timestamps []int64
+	client.WalkMany(
+		context.Background(),
+		[]string{"a", "b", "c"},
+		func(es []*loggregator_v2.Envelope) bool {
+			for _, e := range es {
+				timestamps = append(timestamps, e.GetTimestamp())
+			}
+			return true
+		},
+		r.read,
+		client.WithWalkManyWalkOptions(client.WithWalkLimit(2)),
+	)
+
+	if len(timestamps) != 10 {
+		t.Fatalf("expected 10 envelopes: %v", timestamps)
+	}
+
+	for i, ts := range timestamps {
+		if ts != int64(i+1) {
+			t.Fatalf("expected envelopes in timestamp order: %v", timestamps)
+		}
+	}
+}
+
+func TestWalkManyBoundsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	r := newMultiReader(map[string][]int64{
+		"a": {1, 2, 3},
+		"b": {1, 2, 3},
+		"c": {1, 2, 3},
+		"d": {1, 2, 3},
+	})
+	r.delay = time.Millisecond
+
+	client.WalkMany(
+		context.Background(),
+		[]string{"a", "b", "c", "d"},
+		func([]*loggregator_v2.Envelope) bool { return true },
+		r.read,
+		client.WithWalkManyConcurrency(2),
+		client.WithWalkManyWalkOptions(client.WithWalkLimit(1)),
+	)
+
+	if n := r.maxInFlight.Load(); n > 2 {
+		t.Fatalf("expected at most 2 concurrent reads: %d", n)
+	}
+}
+
+func TestWalkManyReportsErrorsWithoutStoppingOtherSources(t *testing.T) {
+	t.Parallel()
+
+	r := newMultiReader(map[string][]int64{
+		"a": {1, 2, 3},
+	})
+
+	var (
+		mu   sync.Mutex
+		errs = make(map[string]error)
+	)
+
+	var count int
+	client.WalkMany(
+		context.Background(),
+		[]string{"a", "missing"},
+		func(es []*loggregator_v2.Envelope) bool {
+			count += len(es)
+			return true
+		},
+		r.read,
+		client.WithWalkManyErrorHandler(func(sourceID string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs[sourceID] = err
+		}),
+	)
+
+	if count != 3 {
+		t.Fatalf("expected 3 envelopes: %d", count)
+	}
+
+	if len(errs) != 1 || errs["missing"] == nil {
+		t.Fatalf("expected an error for the missing source ID: %v", errs)
+	}
+}
+
+func TestWalkManyStopsWhenVisitorIsDone(t *testing.T) {
+	t.Parallel()
+
+	r := newMultiReader(map[string][]int64{
+		"a": {1, 3, 5},
+		"b": {2, 4, 6},
+	})
+
+	var calls int
+	client.WalkMany(
+		context.Background(),
+		[]string{"a", "b"},
+		func([]*loggregator_v2.Envelope) bool {
+			calls++
+			return false
+		},
+		r.read,
+		client.WithWalkManyWalkOptions(client.WithWalkLimit(1)),
+	)
+
+	if calls != 1 {
+		t.Fatalf("expected visitor to be invoked once: %d", calls)
+	}
+}
+
+// TestWalkManyWithClient walks via a Client, whose reads from several
+// goroutines must not race (see go test -race). The HTTP client does not
+// synchronize its requests, so the race detector sees any unguarded state of
+// the Client.
+func TestWalkManyWithClient(t *testing.T) {
+	t.Parallel()
+
+	c := client.NewClient("http://log-cache.example.com", client.WithHTTPClient(logCacheHTTPClient{}))
+	sourceIDs := []string{"a", "b", "c", "d"}
+
+	var es []*loggregator_v2.Envelope
+	client.WalkMany(
+		context.Background(),
+		sourceIDs,
+		func(b []*loggregator_v2.Envelope) bool {
+			es = append(es, b...)
+			return true
+		},
+		c.Read,
+	)
+
+	if len(es) != len(sourceIDs) {
+		t.Fatalf("expected %d envelopes: %d", len(sourceIDs), len(es))
+	}
+}
+
+// logCacheHTTPClient serves the info endpoint and an envelope with the
+// timestamp 1 for each source ID.
+type logCacheHTTPClient struct{}
+
+func (logCacheHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	body := `{"envelopes": {"batch": []}}`
+
+	sourceID, isRead := strings.CutPrefix(req.URL.Path, "/api/v1/read/")
+	start, _ := strconv.ParseInt(req.URL.Query().Get("start_time"), 10, 64)
+
+	switch {
+	case req.URL.Path == "/api/v1/info":
+		// Give the other source IDs time to request it as well.
+		time.Sleep(10 * time.Millisecond)
+		body = `{"version": "2.0.0"}`
+	case !isRead:
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	case start <= 1:
+		body = fmt.Sprintf(`{"envelopes": {"batch": [{"timestamp": "1", "source_id": %q}]}}`, sourceID)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}, nil
+}
+
+func TestBuildMultiWalker(t *testing.T) {
+	t.Parallel()
+
+	r := newMultiReader(map[string][]int64{
+		"a": {1, 3, 5},
+		"b": {2, 4, 6},
+	})
+
+	es := client.BuildMultiWalker([]string{"a", "b"}, r.read)(
+		context.Background(),
+		time.Unix(0, 2),
+		time.Unix(0, 5),
+	)
+
+	if len(es) != 3 || es[0].GetTimestamp() != 2 || es[2].GetTimestamp() != 4 {
+		t.Fatalf("wrong envelopes: %v", es)
+	}
+}
+
+// multiReader reads from a limitReader per source ID. It is safe for
+// concurrent use.
+type multiReader struct {
+	readers map[string]*limitReader
+	delay   time.Duration
+
+	inFlight    atomic.Int64
+	maxInFlight atomic.Int64
+}
+
+func newMultiReader(timestamps map[string][]int64) *multiReader {
+	readers := make(map[string]*limitReader)
+	for sourceID, tss := range timestamps {
+		var es []*loggregator_v2.Envelope
+		for _, ts := range tss {
+			es = append(es, &loggregator_v2.Envelope{Timestamp: ts, SourceId: sourceID})
+		}
+		readers[sourceID] = newLimitReader(es)
+	}
+
+	return &multiReader{readers: readers}
+}
+
+func (s *multiReader) read(ctx context.Context, sourceID string, start time.Time, opts ...client.ReadOption) ([]*loggregator_v2.Envelope, error) {
+	n := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	for {
+		m := s.maxInFlight.Load()
+		if n <= m || s.maxInFlight.CompareAndSwap(m, n) {
+			break
+		}
+	}
+
+	time.Sleep(s.delay)
+
+	r, ok := s.readers[sourceID]
+	if !ok {
+		return nil, errors.New("unknown source ID")
+	}
+
+	return r.read(ctx, sourceID, start, opts...)
+}
@@ -3,8 +3,11 @@ package client_test
 import (
 	"context"
 	"errors"
-	"net/url"
+	"fmt"
+	"math"
 	"reflect"
+	"slices"
+	"strconv"
 	"testing"
 	"time"
 
@@ -349,15 +352,231 @@ func TestWalkPassesOpts(t *testing.T) {
 		client.WithWalkEnvelopeTypes(rpc.EnvelopeType_LOG, rpc.EnvelopeType_GAUGE),
 	)
 
-	u := &url.URL{}
-	q := u.Query()
+	req := &rpc.ReadRequest{}
 	for _, o := range r.opts[0] {
-		o(u, q)
+		o(req)
 	}
-	u.RawQuery = q.Encode()
 
-	assertQueryParam(u, "limit", "99")
-	assertQueryParam(u, "envelope_types", "LOG", "GAUGE")
+	if req.Limit != 99 {
+		t.Fatalf("expected limit to equal 99: %d", req.Limit)
+	}
+
+	if !reflect.DeepEqual(req.EnvelopeTypes, []rpc.EnvelopeType{rpc.EnvelopeType_LOG, rpc.EnvelopeType_GAUGE}) {
+		t.Fatalf("expected envelope types to equal LOG and GAUGE: %v", req.EnvelopeTypes)
+	}
+}
+
+func TestWalkDoesNotSkipEnvelopesWithSameTimestamp(t *testing.T) {
+	t.Parallel()
+
+	var store []*loggregator_v2.Envelope
+	for i := 0; i < 250; i++ {
+		store = append(store, &loggregator_v2.Envelope{Timestamp: 1, InstanceId: strconv.Itoa(i)})
+	}
+	for i := 0; i < 10; i++ {
+		store = append(store, &loggregator_v2.Envelope{Timestamp: 2, InstanceId: strconv.Itoa(i)})
+	}
+
+	r := newLimitReader(store)
+	seen := make(map[string]int)
+	client.Walk(
+		context.Background(),
+		"some-id",
+		func(es []*loggregator_v2.Envelope) bool {
+			for _, e := range es {
+				seen[fmt.Sprintf("%d/%s", e.Timestamp, e.InstanceId)]++
+			}
+			return true
+		},
+		r.read,
+		client.WithWalkLimit(100),
+	)
+
+	if len(seen) != len(store) {
+		t.Fatalf("expected %d envelopes: %d", len(store), len(seen))
+	}
+
+	for k, n := range seen {
+		if n != 1 {
+			t.Fatalf("expected %s to be visited once: %d", k, n)
+		}
+	}
+}
+
+func TestWalkExitsAtEndTimeAfterFullBatch(t *testing.T) {
+	t.Parallel()
+
+	var store []*loggregator_v2.Envelope
+	for i := 0; i < 10; i++ {
+		store = append(store, &loggregator_v2.Envelope{Timestamp: int64(i)})
+	}
+
+	r := newLimitReader(store)
+	var reads int
+	read := func(ctx context.Context, sourceID string, start time.Time, opts ...client.ReadOption) ([]*loggregator_v2.Envelope, error) {
+		reads++
+		return r.read(ctx, sourceID, start, opts...)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var es []*loggregator_v2.Envelope
+	client.Walk(
+		ctx,
+		"some-id",
+		func(b []*loggregator_v2.Envelope) bool {
+			es = append(es, b...)
+			return true
+		},
+		read,
+		client.WithWalkEndTime(time.Unix(0, 10)),
+		client.WithWalkLimit(10),
+		client.WithWalkBackoff(client.NewAlwaysRetryBackoff(time.Millisecond)),
+	)
+
+	if ctx.Err() != nil {
+		t.Fatal("expected Walk to exit at the end time")
+	}
+
+	if len(es) != 10 {
+		t.Fatalf("expected 10 envelopes: %d", len(es))
+	}
+
+	// The boundary timestamp is only re-read once.
+	if reads != 2 {
+		t.Fatalf("expected 2 reads: %d", reads)
+	}
+}
+
+func TestWalkStopsAtMaxEnvelopes(t *testing.T) {
+	t.Parallel()
+
+	var store []*loggregator_v2.Envelope
+	for i := 1; i <= 50; i++ {
+		store = append(store, &loggregator_v2.Envelope{Timestamp: int64(i)})
+	}
+
+	r := newLimitReader(store)
+	var es []*loggregator_v2.Envelope
+	client.Walk(
+		context.Background(),
+		"some-id",
+		func(b []*loggregator_v2.Envelope) bool {
+			es = append(es, b...)
+			return true
+		},
+		r.read,
+		client.WithWalkLimit(10),
+		client.WithWalkMaxEnvelopes(25),
+	)
+
+	if len(es) != 25 {
+		t.Fatalf("expected 25 envelopes: %d", len(es))
+	}
+
+	if es[24].Timestamp != 25 {
+		t.Fatalf("expected the oldest envelopes: %d", es[24].Timestamp)
+	}
+}
+
+func TestWalkExitsPromptlyWhenCancelledDuringBackoff(t *testing.T) {
+	t.Parallel()
+
+	r := &stubReader{}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.Walk(
+			ctx,
+			"some-id",
+			func([]*loggregator_v2.Envelope) bool { return true },
+			r.read,
+			client.WithWalkBackoff(client.NewAlwaysRetryBackoff(time.Hour)),
+		)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Walk to exit when the context is cancelled")
+	}
+}
+
+func TestExponentialBackoffGrowsDelay(t *testing.T) {
+	t.Parallel()
+
+	b := client.NewExponentialBackoff(
+		client.ExponentialBackoffPolicy{Base: 10 * time.Millisecond, Multiplier: 3},
+		client.ExponentialBackoffPolicy{},
+	)
+
+	var durations []time.Duration
+	for i := 0; i < 3; i++ {
+		start := time.Now()
+		if !b.OnErr(errors.New("some-error")) {
+			t.Fatal("expected to retry")
+		}
+		durations = append(durations, time.Since(start))
+	}
+
+	for i, want := range []time.Duration{10 * time.Millisecond, 30 * time.Millisecond, 90 * time.Millisecond} {
+		if durations[i] < want {
+			t.Fatalf("expected retry %d to wait at least %s: %s", i, want, durations[i])
+		}
+	}
+}
+
+func TestExponentialBackoffHonorsMaxRetriesAndReset(t *testing.T) {
+	t.Parallel()
+
+	b := client.NewExponentialBackoff(
+		client.ExponentialBackoffPolicy{},
+		client.ExponentialBackoffPolicy{Base: time.Nanosecond, MaxRetries: 2},
+	)
+
+	if b.OnErr(errors.New("some-error")) {
+		t.Fatal("expected zero policy to not retry errors")
+	}
+
+	for i := 0; i < 2; i++ {
+		if !b.OnEmpty() {
+			t.Fatalf("expected to retry empty batch %d", i+1)
+		}
+	}
+
+	if b.OnEmpty() {
+		t.Fatal("expected to give up after max retries")
+	}
+
+	b.Reset()
+
+	if !b.OnEmpty() {
+		t.Fatal("expected Reset to reset the retry count")
+	}
+}
+
+func TestExponentialBackoffStopsWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	b := client.NewExponentialBackoff(
+		client.ExponentialBackoffPolicy{Base: time.Hour},
+		client.ExponentialBackoffPolicy{Base: time.Hour},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if b.OnErrContext(ctx, errors.New("some-error")) {
+		t.Fatal("expected to not retry with a cancelled context")
+	}
+
+	if b.OnEmptyContext(ctx) {
+		t.Fatal("expected to not retry with a cancelled context")
+	}
 }
 
 type spyWalker struct {
@@ -408,6 +627,44 @@ func (s *stubBackoff) Reset() {
 	s.resetCalled++
 }
 
+// limitReader reads from a sorted list of envelopes and honors the start
+// time, end time, limit and descending order like LogCache.
+type limitReader struct {
+	envelopes []*loggregator_v2.Envelope
+}
+
+func newLimitReader(envelopes []*loggregator_v2.Envelope) *limitReader {
+	return &limitReader{envelopes: envelopes}
+}
+
+func (s *limitReader) read(ctx context.Context, sourceID string, start time.Time, opts ...client.ReadOption) ([]*loggregator_v2.Envelope, error) {
+	req := &rpc.ReadRequest{Limit: 100, EndTime: math.MaxInt64}
+	for _, o := range opts {
+		o(req)
+	}
+
+	envelopes := s.envelopes
+	if req.Descending {
+		envelopes = slices.Clone(envelopes)
+		slices.Reverse(envelopes)
+	}
+
+	var es []*loggregator_v2.Envelope
+	for _, e := range envelopes {
+		if e.Timestamp < start.UnixNano() || e.Timestamp >= req.EndTime {
+			continue
+		}
+
+		if len(es) == int(req.Limit) {
+			break
+		}
+
+		es = append(es, e)
+	}
+
+	return es, nil
+}
+
 type stubReader struct {
 	sourceIDs []string
 	starts    []int64